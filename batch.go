@@ -0,0 +1,270 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const defaultBatchConcurrency = 4
+
+type BatchOp string
+
+const (
+	BatchCopy      BatchOp = "copy"
+	BatchMove      BatchOp = "move"
+	BatchDelete    BatchOp = "delete"
+	BatchPublish   BatchOp = "publish"
+	BatchUnpublish BatchOp = "unpublish"
+)
+
+type BatchConfig struct {
+	// Concurrency bounds the number of requests in flight. Defaults to 4.
+	Concurrency int
+	// RateLimit caps started operations per second. Zero means unlimited.
+	RateLimit float64
+	// StopOnError skips every item not yet started once one item fails.
+	StopOnError bool
+	// NoWait returns as soon as the API accepts an asynchronous operation
+	// instead of following it through Client.Worker until it finishes.
+	NoWait bool
+}
+
+type BatchItemResult struct {
+	Index  int
+	Op     BatchOp
+	From   string
+	Path   string
+	Result ActionResult
+	// Status is the terminal status of an asynchronous operation.
+	Status  string
+	Err     error
+	Skipped bool
+}
+
+type BatchReport struct {
+	Items []BatchItemResult
+}
+
+func (r *BatchReport) Failed() []BatchItemResult {
+	var out []BatchItemResult
+	for _, item := range r.Items {
+		if item.Err != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (r *BatchReport) Succeeded() int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Err == nil && !item.Skipped {
+			n++
+		}
+	}
+	return n
+}
+
+type batchItem struct {
+	op   BatchOp
+	from string
+	path string
+	call func(ctx context.Context) (ActionResult, error)
+}
+
+// Batch queues resource operations and runs them with bounded concurrency.
+// It is not safe for concurrent use while being built.
+type Batch struct {
+	client *Client
+	cfg    BatchConfig
+	items  []batchItem
+}
+
+func (c *Client) NewBatch(cfg BatchConfig) *Batch {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultBatchConcurrency
+	}
+	return &Batch{client: c, cfg: cfg}
+}
+
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+func (b *Batch) Copy(req CopyMoveRequest) *Batch {
+	return b.add(BatchCopy, req.From, req.Path, func(ctx context.Context) (ActionResult, error) {
+		return b.client.Resources.Copy(ctx, req)
+	})
+}
+
+func (b *Batch) Move(req CopyMoveRequest) *Batch {
+	return b.add(BatchMove, req.From, req.Path, func(ctx context.Context) (ActionResult, error) {
+		return b.client.Resources.Move(ctx, req)
+	})
+}
+
+func (b *Batch) Delete(req DeleteResourceRequest) *Batch {
+	return b.add(BatchDelete, "", req.Path, func(ctx context.Context) (ActionResult, error) {
+		return b.client.Resources.Delete(ctx, req)
+	})
+}
+
+func (b *Batch) Publish(req PublishRequest) *Batch {
+	return b.add(BatchPublish, "", req.Path, func(ctx context.Context) (ActionResult, error) {
+		link, err := b.client.Resources.Publish(ctx, req)
+		return ActionResult{Link: link}, err
+	})
+}
+
+func (b *Batch) Unpublish(req PublishRequest) *Batch {
+	return b.add(BatchUnpublish, "", req.Path, func(ctx context.Context) (ActionResult, error) {
+		link, err := b.client.Resources.Unpublish(ctx, req)
+		return ActionResult{Link: link}, err
+	})
+}
+
+func (b *Batch) add(op BatchOp, from, path string, call func(context.Context) (ActionResult, error)) *Batch {
+	b.items = append(b.items, batchItem{op: op, from: from, path: path, call: call})
+	return b
+}
+
+// Run executes the queued operations. The report always covers every item
+// in queue order; the returned error joins the item failures, or is the
+// context error if ctx ended first.
+func (b *Batch) Run(ctx context.Context) (*BatchReport, error) {
+	report := &BatchReport{Items: make([]BatchItemResult, len(b.items))}
+	for i, item := range b.items {
+		report.Items[i] = BatchItemResult{Index: i, Op: item.op, From: item.from, Path: item.path, Skipped: true}
+	}
+	if len(b.items) == 0 {
+		return report, nil
+	}
+	if !b.cfg.NoWait {
+		if err := b.client.Worker.Start(ctx); err != nil {
+			return report, err
+		}
+	}
+
+	// stop ends the feed on the first failure under StopOnError. Items
+	// already running finish, since their operations may have started.
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+
+	var limiter *tokenBucket
+	if b.cfg.RateLimit > 0 {
+		limiter = newTokenBucket(b.cfg.RateLimit)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < b.cfg.Concurrency && w < len(b.items); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				select {
+				case <-stop:
+					continue
+				default:
+				}
+				res := &report.Items[i]
+				res.Skipped = false
+				res.Result, res.Status, res.Err = b.runItem(ctx, b.items[i])
+				if res.Err != nil && b.cfg.StopOnError {
+					stopOnce.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range b.items {
+		select {
+		case <-stop:
+			break feed
+		default:
+		}
+		if limiter != nil {
+			if err := limiter.wait(ctx, 1); err != nil {
+				break
+			}
+		}
+		select {
+		case indexes <- i:
+		case <-stop:
+			break feed
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	var errs []error
+	for _, item := range report.Items {
+		if item.Err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", item.Op, item.Path, item.Err))
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+func (b *Batch) runItem(ctx context.Context, item batchItem) (ActionResult, string, error) {
+	res, err := item.call(ctx)
+	if err != nil || res.Operation == nil || b.cfg.NoWait {
+		return res, "", err
+	}
+	status, err := b.client.waitOperation(ctx, *res.Operation)
+	if err != nil {
+		return res, status, err
+	}
+	if status != "success" {
+		return res, status, fmt.Errorf("operation %s finished with status %s", res.Operation.ID, status)
+	}
+	return res, status, nil
+}
+
+// finishOperation starts the worker, waits for ref and fails unless the
+// operation succeeded.
+func (c *Client) finishOperation(ctx context.Context, ref OperationRef) error {
+	if err := c.Worker.Start(ctx); err != nil {
+		return err
+	}
+	status, err := c.waitOperation(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if status != "success" {
+		return fmt.Errorf("operation %s finished with status %s", ref.ID, status)
+	}
+	return nil
+}
+
+// waitOperation follows ref through the client's worker until it reaches a
+// terminal status. Polling errors are retried by the worker.
+func (c *Client) waitOperation(ctx context.Context, ref OperationRef) (string, error) {
+	done := make(chan string, 1)
+	unwatch, err := c.Worker.watch(ref, func(e OperationEvent) {
+		if e.Done {
+			select {
+			case done <- e.Status:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	defer unwatch()
+	select {
+	case status := <-done:
+		return status, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
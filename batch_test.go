@@ -0,0 +1,160 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatchRunsAndTracksOperations(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	t.Cleanup(func() {
+		if err := client.Close(context.Background()); err != nil {
+			t.Errorf("close: %v", err)
+		}
+	})
+	srv.Async = true
+	for i := 0; i < 5; i++ {
+		srv.PutFile(fmt.Sprintf("disk:/src/%d.txt", i), []byte("x"), time.Now())
+	}
+	srv.Mkdir("disk:/dst")
+
+	batch := client.NewBatch(BatchConfig{Concurrency: 2})
+	for i := 0; i < 5; i++ {
+		batch.Copy(CopyMoveRequest{From: fmt.Sprintf("disk:/src/%d.txt", i), Path: fmt.Sprintf("disk:/dst/%d.txt", i)})
+	}
+	batch.Delete(DeleteResourceRequest{Path: "disk:/src/0.txt"})
+
+	report, err := batch.Run(context.Background())
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if report.Succeeded() != 6 {
+		t.Fatalf("report = %+v", report.Items)
+	}
+	for _, item := range report.Items[:5] {
+		if item.Result.Operation == nil || item.Status != "success" {
+			t.Fatalf("item %d = %+v", item.Index, item)
+		}
+	}
+	if !srv.Exists("disk:/dst/4.txt") || srv.Exists("disk:/src/0.txt") {
+		t.Fatal("batch did not apply")
+	}
+}
+
+func TestBatchPartialFailure(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+
+	report, err := client.NewBatch(BatchConfig{NoWait: true}).
+		Move(CopyMoveRequest{From: "disk:/missing.txt", Path: "disk:/b.txt"}).
+		Publish(PublishRequest{Path: "disk:/a.txt"}).
+		Run(context.Background())
+	if err == nil {
+		t.Fatal("expected joined error")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatus != http.StatusNotFound {
+		t.Fatalf("err = %v", err)
+	}
+	failed := report.Failed()
+	if len(failed) != 1 || failed[0].Index != 0 {
+		t.Fatalf("failed = %+v", failed)
+	}
+	if report.Items[1].Err != nil || report.Items[1].Result.Link == nil {
+		t.Fatalf("publish = %+v", report.Items[1])
+	}
+}
+
+func TestBatchStopOnError(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		mustFprint(t, w, `{"error":"ForbiddenError"}`)
+	})
+
+	batch := client.NewBatch(BatchConfig{Concurrency: 1, StopOnError: true, NoWait: true})
+	for i := 0; i < 10; i++ {
+		batch.Delete(DeleteResourceRequest{Path: fmt.Sprintf("disk:/%d", i)})
+	}
+	report, err := batch.Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("calls = %d", got)
+	}
+	skipped := 0
+	for _, item := range report.Items {
+		if item.Skipped {
+			skipped++
+		}
+	}
+	if skipped != 9 {
+		t.Fatalf("skipped = %d", skipped)
+	}
+}
+
+func TestBatchStopOnErrorLetsRunningItemsFinish(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("path") == "disk:/slow" {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		mustFprint(t, w, `{"error":"ForbiddenError"}`)
+	})
+
+	report, err := client.NewBatch(BatchConfig{Concurrency: 2, StopOnError: true, NoWait: true}).
+		Delete(DeleteResourceRequest{Path: "disk:/slow"}).
+		Delete(DeleteResourceRequest{Path: "disk:/fails"}).
+		Delete(DeleteResourceRequest{Path: "disk:/later"}).
+		Run(context.Background())
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if report.Items[0].Err != nil || report.Items[0].Skipped {
+		t.Fatalf("running item = %+v", report.Items[0])
+	}
+	if !report.Items[2].Skipped {
+		t.Fatalf("unstarted item = %+v", report.Items[2])
+	}
+}
+
+func TestWaitOperationUnwatchesOnCancel(t *testing.T) {
+	client, _ := newFakeDiskClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.waitOperation(ctx, OperationRef{ID: "never"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait = %v", err)
+	}
+	if n := client.Worker.Len(); n != 0 {
+		t.Fatalf("watchers left = %d", n)
+	}
+}
+
+func TestBatchRateLimit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	batch := client.NewBatch(BatchConfig{Concurrency: 4, RateLimit: 10, NoWait: true})
+	for i := 0; i < 15; i++ {
+		batch.Delete(DeleteResourceRequest{Path: fmt.Sprintf("disk:/%d", i)})
+	}
+	start := time.Now()
+	if _, err := batch.Run(context.Background()); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("15 ops at 10/s finished in %s", elapsed)
+	}
+}
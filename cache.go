@@ -0,0 +1,425 @@
+package yadisk
+
+import (
+	"container/list"
+	"context"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMetaCacheSize = 1024
+	defaultMetaCacheTTL  = time.Minute
+)
+
+// MetaCacheEntry is a cached Resources.GetMeta response. Path is the
+// normalized resource path ("/docs/a.txt") the entry describes.
+type MetaCacheEntry struct {
+	Path     string
+	Resource *Resource
+	Expires  time.Time
+}
+
+// MetaCache stores Resources.GetMeta responses. A key identifies one request,
+// path and fields included. Implementations must be safe for concurrent use.
+type MetaCache interface {
+	Get(key string) (MetaCacheEntry, bool)
+	Set(key string, entry MetaCacheEntry)
+	// Remove drops every entry for exactly path.
+	Remove(path string)
+	// RemoveTree drops every entry for path and the paths below it.
+	RemoveTree(path string)
+	Purge()
+}
+
+type MetaCacheConfig struct {
+	// Backend defaults to an in-memory LRU of 1024 entries.
+	Backend MetaCache
+	// TTL bounds how long an entry is served. Defaults to one minute.
+	TTL time.Duration
+	// RevisionCheck, when positive, makes the client compare
+	// DiskInfo.Revision at most this often before serving from the cache
+	// and drop every entry once the disk has changed. This catches changes
+	// made by other clients, which the client cannot invalidate itself.
+	RevisionCheck time.Duration
+}
+
+// WithMetaCache caches Resources.GetMeta responses. Mutating calls made
+// through the same client drop the entries for the paths they touch, their
+// ancestors and their descendants. Asynchronous operations are invalidated
+// when they are started, so entries read while they run may be stale until
+// TTL expires.
+func WithMetaCache(cfg MetaCacheConfig) Option {
+	return func(c *config) error {
+		if cfg.Backend == nil {
+			cfg.Backend = NewLRUMetaCache(defaultMetaCacheSize)
+		}
+		if cfg.TTL <= 0 {
+			cfg.TTL = defaultMetaCacheTTL
+		}
+		c.metaCache = &cfg
+		return nil
+	}
+}
+
+type metaCache struct {
+	client  *Client
+	backend MetaCache
+	ttl     time.Duration
+	check   time.Duration
+
+	mu       sync.Mutex
+	checked  time.Time
+	revision int64
+	// appRoot is the disk path of the application folder, learned from the
+	// first "app:" path read, so that entries are kept under disk paths.
+	appRoot   string
+	uploadsMu sync.Mutex
+	uploads   map[string]string
+}
+
+func newMetaCache(client *Client, cfg *MetaCacheConfig) *metaCache {
+	if cfg == nil {
+		return nil
+	}
+	return &metaCache{
+		client:  client,
+		backend: cfg.Backend,
+		ttl:     cfg.TTL,
+		check:   cfg.RevisionCheck,
+		uploads: make(map[string]string),
+	}
+}
+
+// view returns an empty cache with the same settings for a scoped client,
+// whose keys would otherwise collide with those of c.
+func (m *metaCache) view(c *Client) *metaCache {
+	if m == nil {
+		return nil
+	}
+	return &metaCache{
+		client:  c,
+		backend: NewLRUMetaCache(defaultMetaCacheSize),
+		ttl:     m.ttl,
+		check:   m.check,
+		uploads: make(map[string]string),
+	}
+}
+
+type uncachedKey struct{}
+
+// uncached makes the GetMeta calls made with ctx skip the cache. Walks use it
+// so that they see the current tree rather than listings cached at
+// different times; what they read is still stored.
+func uncached(ctx context.Context) context.Context {
+	return context.WithValue(ctx, uncachedKey{}, true)
+}
+
+func (m *metaCache) get(ctx context.Context, key string) (*Resource, bool) {
+	if m == nil || ctx.Value(uncachedKey{}) != nil || !m.fresh(ctx) {
+		return nil, false
+	}
+	entry, ok := m.backend.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.Expires) {
+		return nil, false
+	}
+	return cloneResource(entry.Resource), true
+}
+
+func (m *metaCache) set(key, p string, r *Resource) {
+	if m == nil {
+		return
+	}
+	if r.Path != "" {
+		m.learnAppRoot(p, r.Path)
+		p = r.Path
+	}
+	entryPath, ok := m.diskPath(p)
+	if !ok {
+		return
+	}
+	m.backend.Set(key, MetaCacheEntry{Path: entryPath, Resource: cloneResource(r), Expires: time.Now().Add(m.ttl)})
+}
+
+// learnAppRoot records where the application folder is when requested, an
+// "app:" path, was answered with resolved, its "disk:" form.
+func (m *metaCache) learnAppRoot(requested, resolved string) {
+	req, err := ParsePath(requested)
+	if err != nil || req.Scheme() != SchemeApp {
+		return
+	}
+	res, err := ParsePath(resolved)
+	if err != nil || res.Scheme() != SchemeDisk {
+		return
+	}
+	root := res.p
+	if !req.IsRoot() {
+		var ok bool
+		if root, ok = strings.CutSuffix(res.p, req.p); !ok {
+			return
+		}
+	}
+	if root == "" {
+		root = "/"
+	}
+	m.mu.Lock()
+	m.appRoot = root
+	m.mu.Unlock()
+}
+
+// diskPath returns the cache path of p. "app:" paths are mapped into the
+// application folder, and report false while its location is unknown.
+func (m *metaCache) diskPath(p string) (string, bool) {
+	parsed, err := ParsePath(p)
+	if err != nil || parsed.Scheme() != SchemeApp {
+		return cachePath(p), true
+	}
+	m.mu.Lock()
+	root := m.appRoot
+	m.mu.Unlock()
+	if root == "" {
+		return "", false
+	}
+	return cachePath(path.Join(root, parsed.p)), true
+}
+
+// cloneResource copies r deeply enough that callers may modify the copy,
+// its listing and its custom properties without touching the cache.
+func cloneResource(r *Resource) *Resource {
+	c := *r
+	c.CustomProperties, _ = cloneJSONValue(r.CustomProperties).(map[string]any)
+	if r.Embedded.Items != nil {
+		c.Embedded.Items = make([]Resource, len(r.Embedded.Items))
+		for i := range r.Embedded.Items {
+			c.Embedded.Items[i] = *cloneResource(&r.Embedded.Items[i])
+		}
+	}
+	return &c
+}
+
+// cloneJSONValue copies the maps and slices of a decoded JSON value.
+func cloneJSONValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		c := make(map[string]any, len(v))
+		for k, e := range v {
+			c[k] = cloneJSONValue(e)
+		}
+		return c
+	case []any:
+		if v == nil {
+			return v
+		}
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = cloneJSONValue(e)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// fresh reports whether the cache may be served, purging it when the disk
+// revision moved since the last check. The revision is fetched without
+// holding m.mu, so concurrent callers may check at the same time.
+func (m *metaCache) fresh(ctx context.Context) bool {
+	if m.check <= 0 {
+		return true
+	}
+	m.mu.Lock()
+	due := time.Since(m.checked) >= m.check
+	m.mu.Unlock()
+	if !due {
+		return true
+	}
+	info, err := m.client.Disk.Get(ctx, DiskGetRequest{Fields: []string{"revision"}})
+	if err != nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if info.Revision != m.revision {
+		m.backend.Purge()
+		m.revision = info.Revision
+	}
+	m.checked = time.Now()
+	return true
+}
+
+// invalidate drops the entries for paths, everything below them and their
+// ancestors, whose listings embed them.
+func (m *metaCache) invalidate(paths ...string) {
+	if m == nil {
+		return
+	}
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		p, ok := m.diskPath(p)
+		if !ok {
+			// Nothing was read through "app:" yet, but the application
+			// folder may have been read through its disk path.
+			m.backend.Purge()
+			return
+		}
+		m.backend.RemoveTree(p)
+		for p != "/" {
+			p = cacheParent(p)
+			m.backend.Remove(p)
+		}
+	}
+}
+
+func (m *metaCache) purge() {
+	if m != nil {
+		m.backend.Purge()
+	}
+}
+
+// trackUpload remembers the destination of an upload link, whose path is
+// not part of the link itself, until the upload completes.
+func (m *metaCache) trackUpload(link *ResourceUploadLink, path string) {
+	if m == nil || link == nil || link.OperationID == "" {
+		return
+	}
+	m.uploadsMu.Lock()
+	defer m.uploadsMu.Unlock()
+	m.uploads[link.OperationID] = path
+}
+
+func (m *metaCache) uploaded(link *ResourceUploadLink) {
+	if m == nil || link == nil {
+		return
+	}
+	m.uploadsMu.Lock()
+	path, ok := m.uploads[link.OperationID]
+	delete(m.uploads, link.OperationID)
+	m.uploadsMu.Unlock()
+	if ok {
+		m.invalidate(path)
+	}
+}
+
+func cachePath(p string) string {
+	p = stripDiskScheme(p)
+	if len(p) > 1 {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+func cacheParent(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return p[:i]
+}
+
+// NewLRUMetaCache returns an in-memory MetaCache holding at most size
+// entries, evicting the least recently used.
+func NewLRUMetaCache(size int) MetaCache {
+	if size <= 0 {
+		size = defaultMetaCacheSize
+	}
+	return &lruMetaCache{
+		size:   size,
+		order:  list.New(),
+		byKey:  make(map[string]*list.Element),
+		byPath: make(map[string]map[string]struct{}),
+	}
+}
+
+type lruItem struct {
+	key   string
+	entry MetaCacheEntry
+}
+
+type lruMetaCache struct {
+	mu     sync.Mutex
+	size   int
+	order  *list.List
+	byKey  map[string]*list.Element
+	byPath map[string]map[string]struct{}
+}
+
+func (c *lruMetaCache) Get(key string) (MetaCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.byKey[key]
+	if !ok {
+		return MetaCacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruItem).entry, true
+}
+
+func (c *lruMetaCache) Set(key string, entry MetaCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.byKey[key]; ok {
+		c.removeLocked(el)
+	}
+	c.byKey[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	keys := c.byPath[entry.Path]
+	if keys == nil {
+		keys = make(map[string]struct{})
+		c.byPath[entry.Path] = keys
+	}
+	keys[key] = struct{}{}
+	for c.order.Len() > c.size {
+		c.removeLocked(c.order.Back())
+	}
+}
+
+func (c *lruMetaCache) Remove(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removePathLocked(path)
+}
+
+func (c *lruMetaCache) RemoveTree(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	for p := range c.byPath {
+		if p == path || strings.HasPrefix(p, prefix) {
+			c.removePathLocked(p)
+		}
+	}
+}
+
+func (c *lruMetaCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.byKey = make(map[string]*list.Element)
+	c.byPath = make(map[string]map[string]struct{})
+}
+
+func (c *lruMetaCache) removePathLocked(path string) {
+	for key := range c.byPath[path] {
+		c.removeLocked(c.byKey[key])
+	}
+}
+
+func (c *lruMetaCache) removeLocked(el *list.Element) {
+	item := c.order.Remove(el).(*lruItem)
+	delete(c.byKey, item.key)
+	if keys := c.byPath[item.entry.Path]; keys != nil {
+		delete(keys, item.key)
+		if len(keys) == 0 {
+			delete(c.byPath, item.entry.Path)
+		}
+	}
+}
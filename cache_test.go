@@ -0,0 +1,265 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func newCachedClient(t *testing.T, cfg MetaCacheConfig) (*Client, *fakedisk.Server, *int32) {
+	t.Helper()
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	var metaRequests int32
+	client, err := NewClient(
+		WithOAuthToken("token"),
+		WithBaseURL(srv.URL),
+		WithMetaCache(cfg),
+		WithHooks(Hooks{OnRequest: func(r *http.Request) {
+			if r.Method == http.MethodGet && r.URL.Path == "/disk/resources" {
+				atomic.AddInt32(&metaRequests, 1)
+			}
+		}}),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client, srv, &metaRequests
+}
+
+func TestMetaCacheHitsAndInvalidation(t *testing.T) {
+	client, srv, requests := newCachedClient(t, MetaCacheConfig{})
+	srv.Mkdir("disk:/docs")
+	srv.PutFile("disk:/docs/a.txt", []byte("a"), time.Now())
+	ctx := context.Background()
+
+	get := func(path string, fields ...string) *Resource {
+		t.Helper()
+		r, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: path, Fields: fields})
+		if err != nil {
+			t.Fatalf("get meta %s: %v", path, err)
+		}
+		return r
+	}
+
+	get("disk:/docs")
+	get("disk:/docs")
+	get("disk:/docs/a.txt")
+	get("disk:/docs/a.txt", "name")
+	if n := atomic.LoadInt32(requests); n != 3 {
+		t.Fatalf("requests = %d want 3 (path and fields are separate keys)", n)
+	}
+
+	if _, err := client.Resources.Copy(ctx, CopyMoveRequest{From: "disk:/docs/a.txt", Path: "disk:/docs/b.txt"}); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if dir := get("disk:/docs"); len(dir.Embedded.Items) != 2 {
+		t.Fatalf("parent listing not invalidated: %+v", dir.Embedded)
+	}
+	get("disk:/docs/a.txt")
+	if n := atomic.LoadInt32(requests); n != 4 {
+		t.Fatalf("requests = %d want 4 (copy source must stay cached)", n)
+	}
+
+	if _, err := client.Resources.Move(ctx, CopyMoveRequest{From: "disk:/docs", Path: "disk:/archive"}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if _, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/docs/a.txt"}); !isNotFound(err) {
+		t.Fatalf("descendant of moved folder served from cache: %v", err)
+	}
+}
+
+func TestMetaCacheUploadsAndTTL(t *testing.T) {
+	client, srv, requests := newCachedClient(t, MetaCacheConfig{TTL: 50 * time.Millisecond})
+	srv.PutFile("disk:/a.txt", []byte("old"), time.Now())
+	ctx := context.Background()
+
+	if r, _ := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"}); r.Size != 3 {
+		t.Fatalf("size = %d", r.Size)
+	}
+	overwrite := true
+	link, err := client.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: "disk:/a.txt", Overwrite: &overwrite})
+	if err != nil {
+		t.Fatalf("upload url: %v", err)
+	}
+	if _, err := client.Uploads.UploadByLink(ctx, link, strings.NewReader("newer")); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if r, _ := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"}); r.Size != 5 {
+		t.Fatalf("size after upload = %d", r.Size)
+	}
+
+	before := atomic.LoadInt32(requests)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"}); err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != before+1 {
+		t.Fatalf("expired entry was served")
+	}
+}
+
+func TestMetaCacheRevisionCheck(t *testing.T) {
+	client, srv, requests := newCachedClient(t, MetaCacheConfig{TTL: time.Hour, RevisionCheck: time.Nanosecond})
+	srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"}); err != nil {
+			t.Fatalf("get meta: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Fatalf("requests = %d want 1 while the revision is unchanged", n)
+	}
+
+	// A change made by another client bumps the disk revision.
+	srv.PutFile("disk:/a.txt", bytes.Repeat([]byte("b"), 4), time.Now())
+	r, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"})
+	if err != nil || r.Size != 4 {
+		t.Fatalf("size = %v err = %v", r, err)
+	}
+}
+
+func TestLRUMetaCacheEviction(t *testing.T) {
+	c := NewLRUMetaCache(2)
+	for _, p := range []string{"/a", "/b"} {
+		c.Set(p, MetaCacheEntry{Path: p, Resource: &Resource{}})
+	}
+	c.Get("/a")
+	c.Set("/c", MetaCacheEntry{Path: "/c", Resource: &Resource{}})
+	if _, ok := c.Get("/b"); ok {
+		t.Fatal("least recently used entry was kept")
+	}
+	if _, ok := c.Get("/a"); !ok {
+		t.Fatal("recently used entry was evicted")
+	}
+	c.Set("/a/x", MetaCacheEntry{Path: "/a/x", Resource: &Resource{}})
+	c.RemoveTree("/a")
+	if _, ok := c.Get("/a/x"); ok {
+		t.Fatal("descendant survived RemoveTree")
+	}
+}
+
+func TestMetaCacheReturnsCopies(t *testing.T) {
+	client, srv, requests := newCachedClient(t, MetaCacheConfig{TTL: time.Hour})
+	srv.PutFile("disk:/docs/a.txt", []byte("a"), time.Now())
+	ctx := context.Background()
+	if _, err := client.Resources.UpdateMeta(ctx, ResourceUpdateRequest{Path: "disk:/docs/a.txt", CustomProperties: map[string]any{"tags": []any{"x"}}}); err != nil {
+		t.Fatalf("update meta: %v", err)
+	}
+
+	first, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/docs"})
+	if err != nil || len(first.Embedded.Items) != 1 {
+		t.Fatalf("get meta: %v %v", first, err)
+	}
+	first.Embedded.Items[0].Name = "changed"
+	first.Embedded.Items[0].CustomProperties["tags"].([]any)[0] = "changed"
+	first.Embedded.Items[0].CustomProperties["owner"] = "changed"
+
+	second, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/docs"})
+	if err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Fatalf("requests = %d want 1", n)
+	}
+	item := second.Embedded.Items[0]
+	if item.Name != "a.txt" || item.CustomProperties["tags"].([]any)[0] != "x" || item.CustomProperties["owner"] != nil {
+		t.Fatalf("cached item was modified through a returned copy: %+v", item)
+	}
+}
+
+func TestMetaCacheRevisionCheckDoesNotSerializeCallers(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+	var checks int32
+	both := make(chan struct{})
+	client, err := NewClient(
+		WithOAuthToken("token"),
+		WithBaseURL(srv.URL),
+		WithMetaCache(MetaCacheConfig{RevisionCheck: time.Hour}),
+		WithHooks(Hooks{OnRequest: func(r *http.Request) {
+			if r.URL.Path != "/disk" {
+				return
+			}
+			// The first revision check waits for the second one, which
+			// could not start while the first held the cache lock.
+			if atomic.AddInt32(&checks, 1) == 2 {
+				close(both)
+				return
+			}
+			select {
+			case <-both:
+			case <-time.After(5 * time.Second):
+			}
+		}}),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := client.Resources.GetMeta(context.Background(), ResourceGetRequest{Path: "disk:/a.txt"})
+			errs <- err
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("get meta: %v", err)
+		}
+	}
+	select {
+	case <-both:
+	default:
+		t.Fatal("revision checks ran one after the other")
+	}
+}
+
+func TestMetaCacheAppFolderPaths(t *testing.T) {
+	client, srv, requests := newCachedClient(t, MetaCacheConfig{TTL: time.Hour})
+	srv.PutFile(fakedisk.AppFolder+"/a.txt", []byte("a"), time.Now())
+	srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+	ctx := context.Background()
+
+	get := func(path string) {
+		t.Helper()
+		if _, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: path}); err != nil {
+			t.Fatalf("get meta %s: %v", path, err)
+		}
+	}
+	update := func(path string) {
+		t.Helper()
+		if _, err := client.Resources.UpdateMeta(ctx, ResourceUpdateRequest{Path: path, CustomProperties: map[string]any{"k": "v"}}); err != nil {
+			t.Fatalf("update meta %s: %v", path, err)
+		}
+	}
+
+	get("app:/a.txt")
+	get("disk:/a.txt")
+	before := atomic.LoadInt32(requests)
+
+	// A write through the disk path drops the entry read through "app:".
+	update("disk:" + fakedisk.AppFolder + "/a.txt")
+	get("app:/a.txt")
+	if n := atomic.LoadInt32(requests); n != before+1 {
+		t.Fatalf("requests = %d want %d: app entry not invalidated", n, before+1)
+	}
+
+	// A write through "app:" leaves the unrelated disk:/a.txt cached.
+	update("app:/a.txt")
+	get("disk:/a.txt")
+	get("app:/a.txt")
+	if n := atomic.LoadInt32(requests); n != before+2 {
+		t.Fatalf("requests = %d want %d", n, before+2)
+	}
+}
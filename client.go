@@ -12,16 +12,24 @@ type Client struct {
 	transport  *transport
 	retry      RetryPolicy
 	hooks      Hooks
+	log        *clientLogger
+	cache      *metaCache
+	quota      *quotaChecker
+	scope      *pathScope
 	workerCfg  WorkerConfig
 	randSource *rand.Rand
 	randMu     sync.Mutex
 
+	uploadLimiter   *BandwidthLimiter
+	downloadLimiter *BandwidthLimiter
+
 	Disk       *DiskService
 	Resources  *ResourcesService
 	Uploads    *UploadsService
 	Public     *PublicService
 	Trash      *TrashService
 	Operations *OperationsService
+	Sync       *SyncService
 	Worker     *OperationWorker
 }
 
@@ -45,25 +53,43 @@ func NewClient(opts ...Option) (*Client, error) {
 
 	c := &Client{
 		transport: &transport{
-			httpClient: cfg.httpClient,
-			baseURL:    cfg.baseURL,
-			token:      cfg.token,
-			userAgent:  cfg.userAgent,
+			roundTrip: chainMiddleware(cfg.httpClient.Do, cfg.middleware),
+			baseURL:   cfg.baseURL,
+			token:     cfg.token,
+			userAgent: cfg.userAgent,
 		},
 		retry:      cfg.retryPolicy,
 		hooks:      cfg.hooks,
 		workerCfg:  cfg.worker,
 		randSource: rand.New(rand.NewSource(time.Now().UnixNano())),
+
+		uploadLimiter:   NewBandwidthLimiter(cfg.uploadLimit),
+		downloadLimiter: NewBandwidthLimiter(cfg.downloadLimit),
+	}
+
+	c.cache = newMetaCache(c, cfg.metaCache)
+	c.quota = newQuotaChecker(c, cfg.quotaCheck)
+	if cfg.logger != nil {
+		c.log = &clientLogger{l: cfg.logger, levels: cfg.logLevels}
+	}
+
+	if cfg.scope != "" {
+		c.scope = newPathScope(c, MustParsePath(cfg.scope))
 	}
 
+	c.initServices()
+	c.Worker = newOperationWorker(c, cfg.worker)
+	return c, nil
+}
+
+func (c *Client) initServices() {
 	c.Disk = &DiskService{client: c}
 	c.Resources = &ResourcesService{client: c}
 	c.Uploads = &UploadsService{client: c}
 	c.Public = &PublicService{client: c}
 	c.Trash = &TrashService{client: c}
 	c.Operations = &OperationsService{client: c}
-	c.Worker = newOperationWorker(c, cfg.worker)
-	return c, nil
+	c.Sync = &SyncService{client: c}
 }
 
 func (c *Client) Close(ctx context.Context) error {
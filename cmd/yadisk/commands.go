@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2"
+)
+
+func runLs(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("ls", flag.ContinueOnError)
+	limit := fs.Int("limit", 0, "maximum number of entries (0 lists everything)")
+	rest, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var items []yadisk.Resource
+	pageSize := 100
+	for offset := 0; ; {
+		page := offset
+		res, err := e.client.Resources.GetMeta(ctx, yadisk.ResourceGetRequest{Path: rest[0], Limit: &pageSize, Offset: &page})
+		if err != nil {
+			return err
+		}
+		if res.Type != "dir" {
+			items = append(items, *res)
+			break
+		}
+		items = append(items, res.Embedded.Items...)
+		offset += len(res.Embedded.Items)
+		if len(res.Embedded.Items) == 0 || offset >= res.Embedded.Total || (*limit > 0 && len(items) >= *limit) {
+			break
+		}
+	}
+	if *limit > 0 && len(items) > *limit {
+		items = items[:*limit]
+	}
+
+	return e.out.emit(items, func(w io.Writer) {
+		for _, item := range items {
+			size := "-"
+			if item.Type != "dir" {
+				size = humanBytes(item.Size)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Type, size, item.Modified.Time.Local().Format(time.DateTime), item.Name)
+		}
+	})
+}
+
+func runStat(ctx context.Context, e *env, args []string) error {
+	rest, err := parseArgs(flag.NewFlagSet("stat", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := e.client.Resources.GetMeta(ctx, yadisk.ResourceGetRequest{Path: rest[0], Fields: []string{
+		"name", "path", "type", "size", "mime_type", "md5", "sha256", "created", "modified", "revision", "public_url", "custom_properties",
+	}})
+	if err != nil {
+		return err
+	}
+	return e.out.emit(res, func(w io.Writer) {
+		_, _ = fmt.Fprintf(w, "path:\t%s\n", res.Path)
+		_, _ = fmt.Fprintf(w, "type:\t%s\n", res.Type)
+		if res.Type != "dir" {
+			_, _ = fmt.Fprintf(w, "size:\t%d (%s)\n", res.Size, humanBytes(res.Size))
+			_, _ = fmt.Fprintf(w, "mime:\t%s\n", res.MimeType)
+			_, _ = fmt.Fprintf(w, "md5:\t%s\n", res.MD5)
+			_, _ = fmt.Fprintf(w, "sha256:\t%s\n", res.SHA256)
+		}
+		_, _ = fmt.Fprintf(w, "created:\t%s\n", res.Created.Raw)
+		_, _ = fmt.Fprintf(w, "modified:\t%s\n", res.Modified.Raw)
+		_, _ = fmt.Fprintf(w, "revision:\t%d\n", res.Revision)
+		if res.PublicURL != "" {
+			_, _ = fmt.Fprintf(w, "public:\t%s\n", res.PublicURL)
+		}
+		for k, v := range res.CustomProperties {
+			_, _ = fmt.Fprintf(w, "property %s:\t%v\n", k, v)
+		}
+	})
+}
+
+func runCopy(ctx context.Context, e *env, args []string) error {
+	return copyOrMove(ctx, e, "cp", args, e.client.Resources.Copy)
+}
+
+func runMove(ctx context.Context, e *env, args []string) error {
+	return copyOrMove(ctx, e, "mv", args, e.client.Resources.Move)
+}
+
+func copyOrMove(ctx context.Context, e *env, name string, args []string, call func(context.Context, yadisk.CopyMoveRequest) (yadisk.ActionResult, error)) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	overwrite := fs.Bool("overwrite", false, "replace an existing destination")
+	wait := fs.Bool("wait", false, "wait for asynchronous operations to finish")
+	rest, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	res, err := call(ctx, yadisk.CopyMoveRequest{From: rest[0], Path: rest[1], Overwrite: overwrite})
+	if err != nil {
+		return err
+	}
+	return e.finishAction(ctx, res, *wait)
+}
+
+func runRm(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("rm", flag.ContinueOnError)
+	permanent := fs.Bool("permanent", false, "delete permanently instead of moving to trash")
+	wait := fs.Bool("wait", false, "wait for asynchronous operations to finish")
+	rest, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := e.client.Resources.Delete(ctx, yadisk.DeleteResourceRequest{Path: rest[0], Permanently: permanent})
+	if err != nil {
+		return err
+	}
+	return e.finishAction(ctx, res, *wait)
+}
+
+func runMkdir(ctx context.Context, e *env, args []string) error {
+	rest, err := parseArgs(flag.NewFlagSet("mkdir", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	if err := e.client.Resources.MkdirAll(ctx, rest[0]); err != nil {
+		return err
+	}
+	return e.out.emit(map[string]string{"path": rest[0]}, func(w io.Writer) {})
+}
+
+func runPut(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("put", flag.ContinueOnError)
+	overwrite := fs.Bool("overwrite", false, "replace an existing remote file")
+	rest, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	local, remote := rest[0], rest[1]
+
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", local)
+	}
+
+	link, err := e.client.Uploads.GetUploadURL(ctx, yadisk.UploadURLRequest{Path: remote, Overwrite: overwrite})
+	if err != nil {
+		return err
+	}
+
+	var opts []yadisk.TransferOption
+	if e.progress {
+		opts = newProgressBar(e.stderr, filepath.Base(local)).options()
+	}
+	res, err := e.client.Uploads.UploadByLink(ctx, link, f, opts...)
+	if err != nil {
+		return err
+	}
+	return e.out.emit(actionOutput(res), func(w io.Writer) {})
+}
+
+func runGet(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil || fs.NArg() < 1 || fs.NArg() > 2 {
+		return usageError{}
+	}
+	remote := fs.Arg(0)
+	local := fs.Arg(1)
+	if local == "" {
+		local = path.Base(remote)
+	}
+	if info, err := os.Stat(local); err == nil && info.IsDir() {
+		local = filepath.Join(local, path.Base(remote))
+	}
+
+	var opts []yadisk.TransferOption
+	if e.progress {
+		opts = newProgressBar(e.stderr, path.Base(remote)).options()
+	}
+	body, err := e.client.Uploads.OpenDownload(ctx, yadisk.DownloadURLRequest{Path: remote}, opts...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	// Download next to the destination and rename, so a failed transfer
+	// never leaves a partial file in its place.
+	f, err := os.CreateTemp(filepath.Dir(local), ".yadisk-get-*")
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), local)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return e.out.emit(map[string]any{"path": local, "bytes": n}, func(w io.Writer) {})
+}
+
+func runPublish(ctx context.Context, e *env, args []string) error {
+	rest, err := parseArgs(flag.NewFlagSet("publish", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	if _, err := e.client.Resources.Publish(ctx, yadisk.PublishRequest{Path: rest[0]}); err != nil {
+		return err
+	}
+	res, err := e.client.Resources.GetMeta(ctx, yadisk.ResourceGetRequest{Path: rest[0], Fields: []string{"path", "public_key", "public_url"}})
+	if err != nil {
+		return err
+	}
+	out := map[string]string{"path": res.Path, "public_key": res.PublicKey, "public_url": res.PublicURL}
+	return e.out.emit(out, func(w io.Writer) {
+		_, _ = fmt.Fprintln(w, res.PublicURL)
+	})
+}
+
+func runUnpublish(ctx context.Context, e *env, args []string) error {
+	rest, err := parseArgs(flag.NewFlagSet("unpublish", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	link, err := e.client.Resources.Unpublish(ctx, yadisk.PublishRequest{Path: rest[0]})
+	if err != nil {
+		return err
+	}
+	return e.out.emit(link, func(w io.Writer) {})
+}
+
+func runTrash(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return usageError{}
+	}
+	switch args[0] {
+	case "ls":
+		if _, err := parseArgs(flag.NewFlagSet("trash ls", flag.ContinueOnError), args[1:], 0); err != nil {
+			return err
+		}
+		var items []yadisk.TrashResource
+		it := e.client.Trash.Iterate(ctx)
+		for it.Next() {
+			items = append(items, it.Resource())
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return e.out.emit(items, func(w io.Writer) {
+			for _, item := range items {
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Path, item.Type, item.Deleted.Raw, item.OriginPath)
+			}
+		})
+	case "restore":
+		fs := flag.NewFlagSet("trash restore", flag.ContinueOnError)
+		name := fs.String("name", "", "restore under a different name")
+		overwrite := fs.Bool("overwrite", false, "replace an existing resource at the origin path")
+		wait := fs.Bool("wait", false, "wait for asynchronous operations to finish")
+		rest, err := parseArgs(fs, args[1:], 1)
+		if err != nil {
+			return err
+		}
+		res, err := e.client.Trash.Restore(ctx, yadisk.TrashRestoreRequest{Path: rest[0], Name: *name, Overwrite: overwrite})
+		if err != nil {
+			return err
+		}
+		return e.finishAction(ctx, res, *wait)
+	case "empty":
+		fs := flag.NewFlagSet("trash empty", flag.ContinueOnError)
+		wait := fs.Bool("wait", false, "wait for asynchronous operations to finish")
+		fs.SetOutput(io.Discard)
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() > 1 {
+			return usageError{}
+		}
+		res, err := e.client.Trash.Empty(ctx, yadisk.TrashDeleteRequest{Path: fs.Arg(0)})
+		if err != nil {
+			return err
+		}
+		return e.finishAction(ctx, res, *wait)
+	default:
+		return usageError{}
+	}
+}
+
+func runDf(ctx context.Context, e *env, args []string) error {
+	if _, err := parseArgs(flag.NewFlagSet("df", flag.ContinueOnError), args, 0); err != nil {
+		return err
+	}
+	disk, err := e.client.Disk.Get(ctx, yadisk.DiskGetRequest{})
+	if err != nil {
+		return err
+	}
+	return e.out.emit(disk, func(w io.Writer) {
+		_, _ = fmt.Fprintf(w, "total:\t%s\n", humanBytes(disk.TotalSpace))
+		_, _ = fmt.Fprintf(w, "used:\t%s\n", humanBytes(disk.UsedSpace))
+		_, _ = fmt.Fprintf(w, "free:\t%s\n", humanBytes(disk.TotalSpace-disk.UsedSpace))
+		_, _ = fmt.Fprintf(w, "trash:\t%s\n", humanBytes(disk.TrashSize))
+		_, _ = fmt.Fprintf(w, "max file:\t%s\n", humanBytes(disk.MaxFileSize))
+	})
+}
+
+func runOp(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 || args[0] != "wait" {
+		return usageError{}
+	}
+	fs := flag.NewFlagSet("op wait", flag.ContinueOnError)
+	interval := fs.Duration("interval", time.Second, "poll interval")
+	timeout := fs.Duration("timeout", 0, "give up after this long (0 waits forever)")
+	rest, err := parseArgs(fs, args[1:], 1)
+	if err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("-interval must be positive, got %s", *interval)
+	}
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+	status, err := waitOperation(ctx, e.client, rest[0], *interval)
+	if err != nil {
+		return err
+	}
+	if err := e.out.emit(map[string]string{"id": rest[0], "status": status}, func(w io.Writer) {
+		_, _ = fmt.Fprintln(w, status)
+	}); err != nil {
+		return err
+	}
+	if status != "success" {
+		return fmt.Errorf("operation %s finished with status %s", rest[0], status)
+	}
+	return nil
+}
+
+func (e *env) finishAction(ctx context.Context, res yadisk.ActionResult, wait bool) error {
+	out := actionOutput(res)
+	if wait && res.Operation != nil {
+		status, err := waitOperation(ctx, e.client, res.Operation.ID, time.Second)
+		if err != nil {
+			return err
+		}
+		out["status"] = status
+		if status != "success" {
+			return fmt.Errorf("operation %s finished with status %s", res.Operation.ID, status)
+		}
+	}
+	return e.out.emit(out, func(w io.Writer) {
+		if id, ok := out["operation"]; ok {
+			_, _ = fmt.Fprintf(w, "operation:\t%s\n", id)
+		}
+		if status, ok := out["status"]; ok {
+			_, _ = fmt.Fprintf(w, "status:\t%s\n", status)
+		}
+	})
+}
+
+func actionOutput(res yadisk.ActionResult) map[string]any {
+	out := map[string]any{"status_code": res.StatusCode}
+	if res.Operation != nil {
+		out["operation"] = res.Operation.ID
+	}
+	return out
+}
+
+func waitOperation(ctx context.Context, client *yadisk.Client, id string, interval time.Duration) (string, error) {
+	if id == "" {
+		return "", errors.New("operation id is required")
+	}
+	for {
+		status, err := client.Operations.GetStatus(ctx, yadisk.OperationStatusRequest{OperationID: id})
+		if err != nil {
+			return "", err
+		}
+		if status.IsTerminal() {
+			return status.Status, nil
+		}
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return "", ctx.Err()
+		case <-t.C:
+		}
+	}
+}
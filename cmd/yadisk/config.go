@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type config struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"base_url"`
+}
+
+func loadConfig(path string, getenv func(string) string) (*config, error) {
+	explicit := path != ""
+	if path == "" {
+		path = getenv("YADISK_CONFIG")
+		explicit = path != ""
+	}
+	if path == "" {
+		path = defaultConfigPath(getenv)
+	}
+
+	cfg := &config{}
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c *config) override(token, baseURL string, getenv func(string) string) {
+	switch {
+	case token != "":
+		c.Token = token
+	case getenv("YADISK_TOKEN") != "":
+		c.Token = getenv("YADISK_TOKEN")
+	}
+	switch {
+	case baseURL != "":
+		c.BaseURL = baseURL
+	case getenv("YADISK_BASE_URL") != "":
+		c.BaseURL = getenv("YADISK_BASE_URL")
+	}
+}
+
+func defaultConfigPath(getenv func(string) string) string {
+	dir := getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home := getenv("HOME")
+		if home == "" {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "yadisk", "config.json")
+}
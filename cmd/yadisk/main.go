@@ -0,0 +1,154 @@
+// Command yadisk is a command-line client for Yandex Disk built on the
+// yadisk SDK.
+//
+// The OAuth token is taken from the -token flag, the YADISK_TOKEN
+// environment variable or the "token" key of the config file
+// ($XDG_CONFIG_HOME/yadisk/config.json unless -config or YADISK_CONFIG is
+// set), in that order.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/grixate/yandex-disk-go-v2"
+)
+
+type env struct {
+	client   *yadisk.Client
+	out      *printer
+	stderr   io.Writer
+	progress bool
+}
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"ls":        {"ls [-limit N] PATH", runLs},
+	"stat":      {"stat PATH", runStat},
+	"cp":        {"cp [-overwrite] [-wait] FROM TO", runCopy},
+	"mv":        {"mv [-overwrite] [-wait] FROM TO", runMove},
+	"rm":        {"rm [-permanent] [-wait] PATH", runRm},
+	"mkdir":     {"mkdir PATH", runMkdir},
+	"put":       {"put [-overwrite] LOCAL REMOTE", runPut},
+	"get":       {"get REMOTE [LOCAL]", runGet},
+	"publish":   {"publish PATH", runPublish},
+	"unpublish": {"unpublish PATH", runUnpublish},
+	"trash":     {"trash ls | trash restore [-name NAME] [-overwrite] [-wait] PATH | trash empty [-wait] [PATH]", runTrash},
+	"df":        {"df", runDf},
+	"op":        {"op wait [-interval D] [-timeout D] ID", runOp},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Getenv, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+func run(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("yadisk", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	token := fs.String("token", "", "OAuth token (default $YADISK_TOKEN or config file)")
+	configPath := fs.String("config", "", "config file (default $YADISK_CONFIG or $XDG_CONFIG_HOME/yadisk/config.json)")
+	baseURL := fs.String("base-url", "", "API base URL (default $YADISK_BASE_URL or config file)")
+	jsonOut := fs.Bool("json", false, "print machine-readable JSON")
+	noProgress := fs.Bool("no-progress", false, "disable transfer progress bars")
+	fs.Usage = func() { printUsage(stderr, fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 {
+		printUsage(stderr, fs)
+		return 2
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "yadisk: unknown command %q\n", fs.Arg(0))
+		printUsage(stderr, fs)
+		return 2
+	}
+
+	cfg, err := loadConfig(*configPath, getenv)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "yadisk: %v\n", err)
+		return 1
+	}
+	cfg.override(*token, *baseURL, getenv)
+
+	opts := []yadisk.Option{yadisk.WithOAuthToken(cfg.Token), yadisk.WithUserAgent("yadisk-cli")}
+	if cfg.BaseURL != "" {
+		opts = append(opts, yadisk.WithBaseURL(cfg.BaseURL))
+	}
+	client, err := yadisk.NewClient(opts...)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "yadisk: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := client.Close(context.Background()); err != nil {
+			_, _ = fmt.Fprintf(stderr, "yadisk: close: %v\n", err)
+		}
+	}()
+
+	e := &env{
+		client:   client,
+		out:      &printer{w: stdout, json: *jsonOut},
+		stderr:   stderr,
+		progress: !*noProgress && !*jsonOut,
+	}
+	if err := cmd.run(ctx, e, fs.Args()[1:]); err != nil {
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			_, _ = fmt.Fprintf(stderr, "usage: yadisk %s\n", cmd.usage)
+			return 2
+		}
+		_, _ = fmt.Fprintf(stderr, "yadisk: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+type usageError struct{}
+
+func (usageError) Error() string { return "invalid usage" }
+
+func parseArgs(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		return nil, usageError{}
+	}
+	if fs.NArg() != n {
+		return nil, usageError{}
+	}
+	return fs.Args(), nil
+}
+
+func printUsage(w io.Writer, fs *flag.FlagSet) {
+	_, _ = fmt.Fprintln(w, "usage: yadisk [flags] <command> [args]")
+	_, _ = fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "  %s\n", commands[name].usage)
+	}
+	_, _ = fmt.Fprintln(w, "\nflags:")
+	fs.SetOutput(w)
+	fs.PrintDefaults()
+}
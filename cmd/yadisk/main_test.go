@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+type cli struct {
+	t   *testing.T
+	srv *fakedisk.Server
+	env map[string]string
+}
+
+func newCLI(t *testing.T) *cli {
+	t.Helper()
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	return &cli{t: t, srv: srv, env: map[string]string{
+		"YADISK_TOKEN":    "token",
+		"YADISK_BASE_URL": srv.URL,
+		"HOME":            t.TempDir(),
+	}}
+}
+
+func (c *cli) run(args ...string) (string, string, int) {
+	c.t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, func(k string) string { return c.env[k] }, &stdout, &stderr)
+	return stdout.String(), stderr.String(), code
+}
+
+func (c *cli) mustRun(args ...string) string {
+	c.t.Helper()
+	stdout, stderr, code := c.run(args...)
+	if code != 0 {
+		c.t.Fatalf("yadisk %s: exit %d: %s", strings.Join(args, " "), code, stderr)
+	}
+	return stdout
+}
+
+func TestCLIFileLifecycle(t *testing.T) {
+	c := newCLI(t)
+	dir := t.TempDir()
+	local := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(local, []byte("quarterly"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	c.mustRun("mkdir", "disk:/docs")
+	c.mustRun("-no-progress", "put", local, "disk:/docs/report.txt")
+	if got, _ := c.srv.File("disk:/docs/report.txt"); string(got) != "quarterly" {
+		t.Fatalf("uploaded = %q", got)
+	}
+
+	out := c.mustRun("ls", "disk:/docs")
+	if !strings.Contains(out, "report.txt") {
+		t.Fatalf("ls output = %q", out)
+	}
+
+	c.mustRun("cp", "disk:/docs/report.txt", "disk:/docs/copy.txt")
+	c.mustRun("mv", "disk:/docs/copy.txt", "disk:/moved.txt")
+	if !c.srv.Exists("disk:/moved.txt") || c.srv.Exists("disk:/docs/copy.txt") {
+		t.Fatal("copy/move did not land")
+	}
+
+	downloaded := filepath.Join(dir, "out.txt")
+	c.mustRun("-no-progress", "get", "disk:/moved.txt", downloaded)
+	if data, _ := os.ReadFile(downloaded); string(data) != "quarterly" {
+		t.Fatalf("downloaded = %q", data)
+	}
+
+	out = c.mustRun("-json", "stat", "disk:/moved.txt")
+	var meta struct {
+		Path string `json:"path"`
+		Size int64  `json:"size"`
+	}
+	if err := json.Unmarshal([]byte(out), &meta); err != nil {
+		t.Fatalf("stat json: %v: %s", err, out)
+	}
+	if meta.Path != "disk:/moved.txt" || meta.Size != 9 {
+		t.Fatalf("meta = %+v", meta)
+	}
+
+	out = c.mustRun("publish", "disk:/moved.txt")
+	if !strings.Contains(out, "/public/") {
+		t.Fatalf("publish output = %q", out)
+	}
+	c.mustRun("unpublish", "disk:/moved.txt")
+}
+
+func TestCLITrashCommands(t *testing.T) {
+	c := newCLI(t)
+	c.srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+	c.srv.PutFile("disk:/b.txt", []byte("b"), time.Now())
+
+	c.mustRun("rm", "disk:/a.txt")
+	c.mustRun("rm", "disk:/b.txt")
+
+	out := c.mustRun("-json", "trash", "ls")
+	var items []struct {
+		Path       string `json:"path"`
+		OriginPath string `json:"origin_path"`
+	}
+	if err := json.Unmarshal([]byte(out), &items); err != nil {
+		t.Fatalf("trash ls json: %v: %s", err, out)
+	}
+	if len(items) != 2 {
+		t.Fatalf("trash items = %+v", items)
+	}
+
+	c.mustRun("trash", "restore", "-name", "restored.txt", items[0].Path)
+	if !c.srv.Exists("disk:/restored.txt") {
+		t.Fatal("restore did not land")
+	}
+	c.mustRun("trash", "empty")
+	if c.srv.Trashed("disk:/b.txt") {
+		t.Fatal("trash not emptied")
+	}
+}
+
+func TestCLIAsyncWaitAndDf(t *testing.T) {
+	c := newCLI(t)
+	c.srv.Async = true
+	c.srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+
+	out := c.mustRun("-json", "cp", "-wait", "disk:/a.txt", "disk:/b.txt")
+	if !strings.Contains(out, `"status": "success"`) {
+		t.Fatalf("cp output = %q", out)
+	}
+
+	var res struct {
+		Operation string `json:"operation"`
+	}
+	out = c.mustRun("-json", "mv", "disk:/b.txt", "disk:/c.txt")
+	if err := json.Unmarshal([]byte(out), &res); err != nil || res.Operation == "" {
+		t.Fatalf("mv output = %q (%v)", out, err)
+	}
+	if out := c.mustRun("op", "wait", "-interval", "10ms", res.Operation); strings.TrimSpace(out) != "success" {
+		t.Fatalf("op wait output = %q", out)
+	}
+	if _, stderr, code := c.run("op", "wait", "-interval", "0", res.Operation); code != 1 || !strings.Contains(stderr, "-interval") {
+		t.Fatalf("zero interval: code=%d stderr=%q", code, stderr)
+	}
+
+	if out := c.mustRun("df"); !strings.Contains(out, "total:") {
+		t.Fatalf("df output = %q", out)
+	}
+}
+
+func TestCLITokenResolution(t *testing.T) {
+	c := newCLI(t)
+	delete(c.env, "YADISK_TOKEN")
+	if _, stderr, code := c.run("df"); code != 1 || !strings.Contains(stderr, "token") {
+		t.Fatalf("code=%d stderr=%q", code, stderr)
+	}
+
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"token":"from-file"}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	c.mustRun("-config", cfgPath, "df")
+
+	c.env["YADISK_CONFIG"] = filepath.Join(t.TempDir(), "missing.json")
+	if _, _, code := c.run("df"); code != 1 {
+		t.Fatalf("explicit missing config exit = %d", code)
+	}
+}
+
+func TestCLIUsageErrors(t *testing.T) {
+	c := newCLI(t)
+	if _, _, code := c.run(); code != 2 {
+		t.Fatalf("no args exit = %d", code)
+	}
+	if _, _, code := c.run("frobnicate"); code != 2 {
+		t.Fatalf("unknown command exit = %d", code)
+	}
+	if _, stderr, code := c.run("cp", "only-one"); code != 2 || !strings.Contains(stderr, "usage: yadisk cp") {
+		t.Fatalf("code=%d stderr=%q", code, stderr)
+	}
+	if _, _, code := c.run("stat", "disk:/missing"); code != 1 {
+		t.Fatalf("missing resource exit = %d", code)
+	}
+}
+
+func TestCLIProgressBar(t *testing.T) {
+	c := newCLI(t)
+	local := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(local, bytes.Repeat([]byte("x"), 64<<10), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	_, stderr, code := c.run("put", local, "disk:/big.bin")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "big.bin [") || !strings.Contains(stderr, "100.0%") {
+		t.Fatalf("stderr = %q", stderr)
+	}
+}
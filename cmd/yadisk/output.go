@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2"
+)
+
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+// emit prints v as indented JSON in -json mode and calls text otherwise.
+func (p *printer) emit(v any, text func(w io.Writer)) error {
+	if p.json {
+		enc := json.NewEncoder(p.w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	text(tw)
+	return tw.Flush()
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// progressBar renders yadisk.TransferProgress reports as a single line.
+type progressBar struct {
+	w     io.Writer
+	label string
+}
+
+func newProgressBar(w io.Writer, label string) *progressBar {
+	return &progressBar{w: w, label: label}
+}
+
+func (b *progressBar) options() []yadisk.TransferOption {
+	return []yadisk.TransferOption{
+		yadisk.WithProgress(b.update),
+		yadisk.WithProgressInterval(100 * time.Millisecond),
+	}
+}
+
+func (b *progressBar) update(p yadisk.TransferProgress) {
+	const width = 30
+	filled := width
+	pct := 100.0
+	if p.Total > 0 {
+		pct = float64(p.BytesDone) * 100 / float64(p.Total)
+		filled = int(float64(width) * float64(p.BytesDone) / float64(p.Total))
+		if filled > width {
+			filled = width
+		}
+	}
+	line := fmt.Sprintf("\r%s [%s%s] %5.1f%% %s/s", b.label, strings.Repeat("=", filled), strings.Repeat(" ", width-filled), pct, humanBytes(int64(p.Rate)))
+	if p.ETA > 0 {
+		line += " eta " + p.ETA.Round(time.Second).String()
+	}
+	_, _ = fmt.Fprint(b.w, line)
+	if p.Done {
+		_, _ = fmt.Fprintln(b.w)
+	}
+}
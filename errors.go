@@ -26,3 +26,8 @@ func (e *APIError) Error() string {
 	}
 	return fmt.Sprintf("yadisk api error %d", e.HTTPStatus)
 }
+
+// Is makes API errors with status 412 match ErrPreconditionFailed.
+func (e *APIError) Is(target error) bool {
+	return target == ErrPreconditionFailed && e != nil && e.HTTPStatus == 412
+}
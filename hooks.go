@@ -1,7 +1,9 @@
 package yadisk
 
 import (
+	"context"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -10,13 +12,104 @@ type Hooks struct {
 	OnResponse       func(*http.Response, time.Duration)
 	OnRetry          func(RetryEvent)
 	OnOperationEvent func(OperationEvent)
+	// OnTransferProgress receives the progress of every upload and download
+	// made through the client.
+	OnTransferProgress func(TransferProgress)
+	// OnCall is invoked when a service method such as "Resources.Copy"
+	// starts, and for every part sent by Uploads.UploadInChunks
+	// ("Uploads.UploadChunk"). The returned context is used for the call's
+	// requests and the returned function receives the call's error.
+	OnCall func(ctx context.Context, name string) (context.Context, func(error))
 }
 
 type RetryEvent struct {
-	Attempt     int
-	Method      string
+	Attempt int
+	Method  string
+	// Endpoint is the templated API path, as returned by RequestEndpoint.
+	Endpoint    string
 	URL         string
 	StatusCode  int
 	Err         error
 	NextBackoff time.Duration
 }
+
+// Endpoints reported for requests to signed upload and download hrefs.
+const (
+	EndpointUpload   = "upload"
+	EndpointDownload = "download"
+)
+
+type endpointKey struct{}
+
+// RequestEndpoint returns a low-cardinality name for the API endpoint a
+// request passed to Hooks.OnRequest or Hooks.OnResponse targets, such as
+// "/disk/resources/copy" or "/disk/operations/{id}". Requests to signed
+// hrefs report EndpointUpload or EndpointDownload. It returns "" for
+// requests not made by the client.
+func RequestEndpoint(req *http.Request) string {
+	if req == nil {
+		return ""
+	}
+	endpoint, _ := req.Context().Value(endpointKey{}).(string)
+	return endpoint
+}
+
+func endpointTemplate(path string) string {
+	if strings.HasPrefix(path, "/disk/operations/") {
+		return "/disk/operations/{id}"
+	}
+	return path
+}
+
+// ComposeHooks returns Hooks that invoke each of hooks in order, so that
+// instrumentation packages can be combined with application hooks.
+func ComposeHooks(hooks ...Hooks) Hooks {
+	var out Hooks
+	for _, h := range hooks {
+		out.OnRequest = chain1(out.OnRequest, h.OnRequest)
+		out.OnRetry = chain1(out.OnRetry, h.OnRetry)
+		out.OnOperationEvent = chain1(out.OnOperationEvent, h.OnOperationEvent)
+		out.OnTransferProgress = chain1(out.OnTransferProgress, h.OnTransferProgress)
+		if prev, next := out.OnResponse, h.OnResponse; prev == nil {
+			out.OnResponse = next
+		} else if next != nil {
+			out.OnResponse = func(resp *http.Response, d time.Duration) {
+				prev(resp, d)
+				next(resp, d)
+			}
+		}
+		if prev, next := out.OnCall, h.OnCall; prev == nil {
+			out.OnCall = next
+		} else if next != nil {
+			out.OnCall = func(ctx context.Context, name string) (context.Context, func(error)) {
+				ctx, endPrev := prev(ctx, name)
+				ctx, endNext := next(ctx, name)
+				return ctx, func(err error) {
+					endNext(err)
+					endPrev(err)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func chain1[T any](prev, next func(T)) func(T) {
+	if prev == nil {
+		return next
+	}
+	if next == nil {
+		return prev
+	}
+	return func(v T) {
+		prev(v)
+		next(v)
+	}
+}
+
+func (c *Client) startCall(ctx context.Context, name string) (context.Context, func(error)) {
+	if c.hooks.OnCall == nil {
+		return ctx, func(error) {}
+	}
+	return c.hooks.OnCall(ctx, name)
+}
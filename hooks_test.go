@@ -0,0 +1,93 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type callKey struct{}
+
+func TestComposeHooksOrder(t *testing.T) {
+	var got []string
+	record := func(name string) Hooks {
+		return Hooks{
+			OnRetry: func(RetryEvent) { got = append(got, name+" retry") },
+			OnCall: func(ctx context.Context, call string) (context.Context, func(error)) {
+				got = append(got, name+" start "+call)
+				return context.WithValue(ctx, callKey{}, name), func(err error) {
+					got = append(got, name+" end "+err.Error())
+				}
+			},
+		}
+	}
+	hooks := ComposeHooks(record("a"), Hooks{}, record("b"))
+
+	hooks.OnRetry(RetryEvent{})
+	ctx, end := hooks.OnCall(context.Background(), "Disk.Get")
+	if v := ctx.Value(callKey{}); v != "b" {
+		t.Fatalf("context value = %v", v)
+	}
+	end(errors.New("boom"))
+
+	want := []string{"a retry", "b retry", "a start Disk.Get", "b start Disk.Get", "b end boom", "a end boom"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("calls = %q\nwant %q", got, want)
+	}
+	if hooks.OnResponse != nil {
+		t.Fatal("unset hooks must stay nil")
+	}
+}
+
+func TestOnCallWrapsServiceMethods(t *testing.T) {
+	var calls []string
+	var requestSawCall bool
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mustFprint(t, w, `{}`)
+	})
+	client.hooks.OnCall = func(ctx context.Context, name string) (context.Context, func(error)) {
+		calls = append(calls, name)
+		return context.WithValue(ctx, callKey{}, name), func(error) {}
+	}
+	client.hooks.OnRequest = func(r *http.Request) {
+		requestSawCall = r.Context().Value(callKey{}) == "Disk.Get"
+	}
+
+	if _, err := client.Disk.Get(context.Background(), DiskGetRequest{}); err != nil {
+		t.Fatalf("disk get: %v", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"Disk.Get"}) || !requestSawCall {
+		t.Fatalf("calls = %v, request saw call = %v", calls, requestSawCall)
+	}
+}
+
+func TestRequestEndpoint(t *testing.T) {
+	var endpoints []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		mustFprint(t, w, `{"status":"success"}`)
+	})
+	client.hooks.OnResponse = func(resp *http.Response, _ time.Duration) {
+		endpoints = append(endpoints, RequestEndpoint(resp.Request))
+	}
+
+	ctx := context.Background()
+	if _, err := client.Operations.GetStatus(ctx, OperationStatusRequest{OperationID: "op-1"}); err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+	if _, err := client.Disk.Get(ctx, DiskGetRequest{}); err != nil {
+		t.Fatalf("disk get: %v", err)
+	}
+	want := []string{"/disk/operations/{id}", "/disk"}
+	if !reflect.DeepEqual(endpoints, want) {
+		t.Fatalf("endpoints = %q want %q", endpoints, want)
+	}
+	if got := RequestEndpoint(httptest.NewRequest(http.MethodGet, "/", nil)); got != "" {
+		t.Fatalf("foreign request endpoint = %q", got)
+	}
+}
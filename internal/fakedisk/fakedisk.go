@@ -0,0 +1,474 @@
+// Package fakedisk is an in-memory stand-in for the Yandex Disk REST API used
+// by the SDK and CLI tests.
+package fakedisk
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type node struct {
+	id        string
+	dir       bool
+	data      []byte
+	created   time.Time
+	modified  time.Time
+	revision  int64
+	props     map[string]any
+	publicKey string
+	settings  map[string]any
+}
+
+type upload struct {
+	path      string
+	overwrite bool
+	buf       []byte
+}
+
+type Server struct {
+	*httptest.Server
+
+	// Async makes copy, move and restore answer 202 with an operation link.
+	Async bool
+	// TotalSpace is the reported disk size. Defaults to 1 TiB.
+	TotalSpace int64
+	// MaxFileSize is reported as max_file_size when set.
+	MaxFileSize int64
+
+	mu         sync.Mutex
+	nodes      map[string]*node
+	uploads    map[string]*upload
+	trash      map[string]*trashEntry
+	operations map[string]string
+	revision   int64
+	seq        int
+	now        func() time.Time
+}
+
+func New() *Server {
+	s := &Server{
+		nodes:      map[string]*node{"/": {dir: true}},
+		uploads:    make(map[string]*upload),
+		trash:      make(map[string]*trashEntry),
+		operations: make(map[string]string),
+		now:        time.Now,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// AppFolder is the disk path "app:" paths resolve to.
+const AppFolder = "/Applications/fakedisk"
+
+// Normalize maps "disk:/a/b", "/a/b" and "a/b" to "/a/b", and "app:/a" to
+// AppFolder + "/a".
+func Normalize(p string) string {
+	if rest, ok := strings.CutPrefix(p, "app:"); ok {
+		return path.Clean(AppFolder + "/" + rest)
+	}
+	p = strings.TrimPrefix(p, "disk:")
+	return path.Clean("/" + p)
+}
+
+func (s *Server) PutFile(p string, data []byte, modified time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p = Normalize(p)
+	s.mkdirAllLocked(path.Dir(p))
+	s.writeLocked(p, data)
+	s.nodes[p].modified = modified
+}
+
+func (s *Server) Mkdir(p string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mkdirAllLocked(Normalize(p))
+}
+
+func (s *Server) File(p string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.nodes[Normalize(p)]
+	if !ok || n.dir {
+		return nil, false
+	}
+	return append([]byte(nil), n.data...), true
+}
+
+func (s *Server) Exists(p string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.nodes[Normalize(p)]
+	return ok
+}
+
+func (s *Server) Remove(p string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(Normalize(p))
+}
+
+func (s *Server) mkdirAllLocked(p string) {
+	if n, ok := s.nodes[p]; ok && n.dir {
+		return
+	}
+	if p != "/" {
+		s.mkdirAllLocked(path.Dir(p))
+	}
+	now := s.now()
+	s.revision++
+	s.nodes[p] = &node{id: s.newIDLocked("res-"), dir: true, created: now, modified: now, revision: s.revision}
+}
+
+func (s *Server) writeLocked(p string, data []byte) {
+	now := s.now()
+	s.revision++
+	n, ok := s.nodes[p]
+	if !ok {
+		n = &node{id: s.newIDLocked("res-"), created: now}
+		s.nodes[p] = n
+	}
+	n.data = append([]byte(nil), data...)
+	n.modified = now
+	n.revision = s.revision
+}
+
+func (s *Server) newIDLocked(prefix string) string {
+	s.seq++
+	return prefix + strconv.Itoa(s.seq)
+}
+
+func (s *Server) removeLocked(p string) {
+	for k := range s.nodes {
+		if k == p || strings.HasPrefix(k, p+"/") {
+			delete(s.nodes, k)
+		}
+	}
+	s.revision++
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	switch {
+	case r.URL.Path == "/disk" && r.Method == http.MethodGet:
+		total := s.TotalSpace
+		if total == 0 {
+			total = 1 << 40
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"total_space": total, "max_file_size": s.MaxFileSize, "used_space": s.usedLocked(), "revision": s.revision})
+	case r.URL.Path == "/disk/resources":
+		s.serveResource(w, r, Normalize(q.Get("path")))
+	case r.URL.Path == "/disk/resources/files" && r.Method == http.MethodGet:
+		s.serveFiles(w, r)
+	case r.URL.Path == "/disk/resources/copy" && r.Method == http.MethodPost:
+		s.serveCopyMove(w, r, false)
+	case r.URL.Path == "/disk/resources/move" && r.Method == http.MethodPost:
+		s.serveCopyMove(w, r, true)
+	case r.URL.Path == "/disk/resources/publish" && r.Method == http.MethodPut:
+		s.servePublish(w, r, true)
+	case r.URL.Path == "/disk/resources/unpublish" && r.Method == http.MethodPut:
+		s.servePublish(w, r, false)
+	case r.URL.Path == "/disk/public/resources" && r.Method == http.MethodGet:
+		s.servePublicResource(w, r)
+	case r.URL.Path == "/disk/public/resources/download" && r.Method == http.MethodGet:
+		p, _, ok := s.publicPathLocked(q.Get("public_key"), q.Get("path"))
+		if n, exists := s.nodes[p]; !ok || !exists || n.dir {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"href": s.URL + "/download?path=" + url.QueryEscape(p), "method": "GET", "templated": false})
+	case r.URL.Path == "/disk/public/resources/public-settings":
+		s.servePublicSettings(w, r)
+	case r.URL.Path == "/disk/trash/resources":
+		s.serveTrash(w, r)
+	case r.URL.Path == "/disk/trash/resources/restore" && r.Method == http.MethodPut:
+		s.serveRestore(w, r)
+	case strings.HasPrefix(r.URL.Path, "/disk/operations/") && r.Method == http.MethodGet:
+		s.serveOperation(w, strings.TrimPrefix(r.URL.Path, "/disk/operations/"))
+	case r.URL.Path == "/disk/resources/upload" && r.Method == http.MethodGet:
+		s.serveUploadLink(w, r, Normalize(q.Get("path")), q.Get("overwrite") == "true")
+	case strings.HasPrefix(r.URL.Path, "/upload/"):
+		s.serveUpload(w, r, strings.TrimPrefix(r.URL.Path, "/upload/"))
+	case r.URL.Path == "/disk/resources/download":
+		p := Normalize(q.Get("path"))
+		if n, ok := s.nodes[p]; !ok || n.dir {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"href": s.URL + "/download?path=" + url.QueryEscape(p), "method": "GET", "templated": false})
+	case r.URL.Path == "/download":
+		n, ok := s.nodes[q.Get("path")]
+		if !ok || n.dir {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		_, _ = w.Write(n.data)
+	default:
+		writeError(w, http.StatusNotFound, "NotFoundError")
+	}
+}
+
+func (s *Server) serveResource(w http.ResponseWriter, r *http.Request, p string) {
+	switch r.Method {
+	case http.MethodGet:
+		n, ok := s.nodes[p]
+		if !ok {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		out := s.resourceLocked(p, n)
+		if n.dir {
+			limit := atoiDefault(r.URL.Query().Get("limit"), 20)
+			offset := atoiDefault(r.URL.Query().Get("offset"), 0)
+			children := s.childrenLocked(p)
+			items := []map[string]any{}
+			for i := offset; i < len(children) && i < offset+limit; i++ {
+				items = append(items, s.resourceLocked(children[i], s.nodes[children[i]]))
+			}
+			out["_embedded"] = map[string]any{"path": diskPath(p), "limit": limit, "offset": offset, "total": len(children), "items": items}
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPut:
+		if n, ok := s.nodes[p]; ok {
+			if n.dir {
+				writeError(w, http.StatusConflict, "DiskPathPointsToExistentDirectoryError")
+				return
+			}
+			writeError(w, http.StatusConflict, "DiskResourceAlreadyExistsError")
+			return
+		}
+		if parent, ok := s.nodes[path.Dir(p)]; !ok || !parent.dir {
+			writeError(w, http.StatusConflict, "DiskPathDoesntExistsError")
+			return
+		}
+		s.mkdirAllLocked(p)
+		writeJSON(w, http.StatusCreated, map[string]any{"href": s.URL + "/disk/resources?path=" + url.QueryEscape(diskPath(p)), "method": "GET", "templated": false})
+	case http.MethodDelete:
+		n, ok := s.nodes[p]
+		if !ok {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		if want := r.URL.Query().Get("md5"); want != "" && !n.dir && want != md5Hex(n.data) {
+			writeError(w, http.StatusPreconditionFailed, "PreconditionFailedError")
+			return
+		}
+		if r.URL.Query().Get("permanently") == "true" {
+			s.removeLocked(p)
+		} else {
+			s.trashLocked(p)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodPatch:
+		n, ok := s.nodes[p]
+		if !ok {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		var body struct {
+			CustomProperties map[string]any `json:"custom_properties"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, "FieldValidationError")
+			return
+		}
+		// Properties merge into the existing ones; null deletes a key.
+		if n.props == nil {
+			n.props = make(map[string]any)
+		}
+		for k, v := range body.CustomProperties {
+			if v == nil {
+				delete(n.props, k)
+				continue
+			}
+			n.props[k] = v
+		}
+		s.revision++
+		n.revision = s.revision
+		writeJSON(w, http.StatusOK, s.resourceLocked(p, n))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowedError")
+	}
+}
+
+// serveFiles lists every file on the disk sorted by path, as the flat
+// /disk/resources/files listing does.
+func (s *Server) serveFiles(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := atoiDefault(q.Get("limit"), 20)
+	offset := atoiDefault(q.Get("offset"), 0)
+	var media map[string]bool
+	if m := q.Get("media_type"); m != "" {
+		media = make(map[string]bool)
+		for _, t := range strings.Split(m, ",") {
+			media[t] = true
+		}
+	}
+	var files []string
+	for p, n := range s.nodes {
+		if !n.dir && (media == nil || media[mediaType(p)]) {
+			files = append(files, p)
+		}
+	}
+	sort.Strings(files)
+	items := []map[string]any{}
+	for i := offset; i < len(files) && i < offset+limit; i++ {
+		items = append(items, s.resourceLocked(files[i], s.nodes[files[i]]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items, "limit": limit, "offset": offset})
+}
+
+func mediaType(p string) string {
+	switch strings.ToLower(path.Ext(p)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".heic":
+		return "image"
+	case ".mp4", ".mov", ".avi":
+		return "video"
+	case ".mp3", ".flac", ".wav":
+		return "audio"
+	case ".txt", ".pdf", ".doc", ".docx", ".md":
+		return "document"
+	default:
+		return "unknown"
+	}
+}
+
+func (s *Server) serveUploadLink(w http.ResponseWriter, r *http.Request, p string, overwrite bool) {
+	if n, ok := s.nodes[p]; ok && (n.dir || !overwrite) {
+		writeError(w, http.StatusConflict, "DiskResourceAlreadyExistsError")
+		return
+	}
+	if parent, ok := s.nodes[path.Dir(p)]; !ok || !parent.dir {
+		writeError(w, http.StatusConflict, "DiskPathDoesntExistsError")
+		return
+	}
+	s.seq++
+	id := "up-" + strconv.Itoa(s.seq)
+	s.uploads[id] = &upload{path: p, overwrite: overwrite}
+	writeJSON(w, http.StatusOK, map[string]any{"href": s.URL + "/upload/" + id, "method": "PUT", "templated": false, "operation_id": id})
+}
+
+func (s *Server) serveUpload(w http.ResponseWriter, r *http.Request, id string) {
+	up, ok := s.uploads[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NotFoundError")
+		return
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequestError")
+		return
+	}
+	up.buf = append(up.buf, data...)
+
+	if cr := r.Header.Get("Content-Range"); cr != "" {
+		var start, end, total int64
+		if _, err := fmt.Sscanf(cr, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+			writeError(w, http.StatusBadRequest, "BadRequestError")
+			return
+		}
+		if end+1 < total {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+	}
+	delete(s.uploads, id)
+	// Like the real uploader, a link issued without overwrite fails when
+	// the file was created after the link was issued.
+	if n, ok := s.nodes[up.path]; ok && (n.dir || !up.overwrite) {
+		writeError(w, http.StatusConflict, "DiskResourceAlreadyExistsError")
+		return
+	}
+	s.writeLocked(up.path, up.buf)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) childrenLocked(p string) []string {
+	var out []string
+	for k := range s.nodes {
+		if k != p && path.Dir(k) == p {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (s *Server) usedLocked() int64 {
+	var total int64
+	for _, n := range s.nodes {
+		total += int64(len(n.data))
+	}
+	return total
+}
+
+func (s *Server) resourceLocked(p string, n *node) map[string]any {
+	out := map[string]any{
+		"resource_id": n.id,
+		"path":        diskPath(p),
+		"name":        path.Base(p),
+		"created":     n.created.UTC().Format(time.RFC3339),
+		"modified":    n.modified.UTC().Format(time.RFC3339),
+		"revision":    n.revision,
+	}
+	if n.dir {
+		out["type"] = "dir"
+	} else {
+		out["type"] = "file"
+		out["size"] = len(n.data)
+		out["md5"] = md5Hex(n.data)
+		out["media_type"] = mediaType(p)
+		sum := sha256.Sum256(n.data)
+		out["sha256"] = hex.EncodeToString(sum[:])
+	}
+	if len(n.props) > 0 {
+		out["custom_properties"] = n.props
+	}
+	if n.publicKey != "" {
+		out["public_key"] = n.publicKey
+		out["public_url"] = s.URL + "/public/" + n.publicKey
+	}
+	return out
+}
+
+func diskPath(p string) string {
+	return "disk:" + p
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func atoiDefault(s string, def int) int {
+	if v, err := strconv.Atoi(s); err == nil {
+		return v
+	}
+	return def
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]any{"error": code, "message": code})
+}
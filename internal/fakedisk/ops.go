@@ -0,0 +1,311 @@
+package fakedisk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type trashEntry struct {
+	origin  string
+	deleted time.Time
+	// nodes holds the deleted subtree keyed relative to origin ("" is the
+	// deleted resource itself).
+	nodes map[string]*node
+}
+
+// TrashPath maps "trash:/a" and "/a" to "/a".
+func TrashPath(p string) string {
+	p = strings.TrimPrefix(p, "trash:")
+	return path.Clean("/" + p)
+}
+
+func (s *Server) Trashed(origin string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	origin = Normalize(origin)
+	for _, e := range s.trash {
+		if e.origin == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) PutTrash(origin string, data []byte, deleted time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	origin = Normalize(origin)
+	now := s.now()
+	s.revision++
+	return s.addTrashLocked(origin, map[string]*node{"": {data: data, created: now, modified: now, revision: s.revision}}, deleted)
+}
+
+func (s *Server) trashLocked(p string) {
+	sub := make(map[string]*node)
+	for k, n := range s.nodes {
+		if k == p || strings.HasPrefix(k, p+"/") {
+			sub[strings.TrimPrefix(k, p)] = n
+		}
+	}
+	s.removeLocked(p)
+	s.addTrashLocked(p, sub, s.now())
+}
+
+func (s *Server) addTrashLocked(origin string, sub map[string]*node, deleted time.Time) string {
+	name := "/" + path.Base(origin)
+	for i := 1; ; i++ {
+		if _, ok := s.trash[name]; !ok {
+			break
+		}
+		name = "/" + path.Base(origin) + "_" + strconv.Itoa(i)
+	}
+	s.trash[name] = &trashEntry{origin: origin, deleted: deleted, nodes: sub}
+	return "trash:" + name
+}
+
+func (s *Server) serveCopyMove(w http.ResponseWriter, r *http.Request, move bool) {
+	q := r.URL.Query()
+	from, to := Normalize(q.Get("from")), Normalize(q.Get("path"))
+	if _, ok := s.nodes[from]; !ok {
+		writeError(w, http.StatusNotFound, "DiskNotFoundError")
+		return
+	}
+	if _, ok := s.nodes[to]; ok {
+		if q.Get("overwrite") != "true" {
+			writeError(w, http.StatusConflict, "DiskResourceAlreadyExistsError")
+			return
+		}
+		s.removeLocked(to)
+	}
+	if parent, ok := s.nodes[path.Dir(to)]; !ok || !parent.dir {
+		writeError(w, http.StatusConflict, "DiskPathDoesntExistsError")
+		return
+	}
+
+	s.revision++
+	for k, n := range s.nodes {
+		if k == from || strings.HasPrefix(k, from+"/") {
+			cp := *n
+			cp.revision = s.revision
+			if !move {
+				cp.id = s.newIDLocked("res-")
+			}
+			s.nodes[to+strings.TrimPrefix(k, from)] = &cp
+		}
+	}
+	if move {
+		s.removeLocked(from)
+	}
+	s.respondAction(w, to)
+}
+
+// respondAction answers a mutating request either synchronously or, when
+// Async is set, with a 202 pointing at an already finished operation.
+func (s *Server) respondAction(w http.ResponseWriter, p string) {
+	if s.Async {
+		s.seq++
+		id := "op-" + strconv.Itoa(s.seq)
+		s.operations[id] = "success"
+		writeJSON(w, http.StatusAccepted, map[string]any{"href": s.URL + "/disk/operations/" + id, "method": "GET", "templated": false})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{"href": s.URL + "/disk/resources?path=" + url.QueryEscape(diskPath(p)), "method": "GET", "templated": false})
+}
+
+func (s *Server) servePublish(w http.ResponseWriter, r *http.Request, publish bool) {
+	p := Normalize(r.URL.Query().Get("path"))
+	n, ok := s.nodes[p]
+	if !ok {
+		writeError(w, http.StatusNotFound, "DiskNotFoundError")
+		return
+	}
+	if publish {
+		var body struct {
+			PublicSettings map[string]any `json:"public_settings"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeError(w, http.StatusBadRequest, "FieldValidationError")
+				return
+			}
+		}
+		if n.publicKey == "" {
+			s.seq++
+			n.publicKey = "pk-" + strconv.Itoa(s.seq)
+			n.settings = make(map[string]any)
+		}
+		for k, v := range body.PublicSettings {
+			n.settings[k] = v
+		}
+	} else {
+		n.publicKey = ""
+		n.settings = nil
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"href": s.URL + "/disk/resources?path=" + url.QueryEscape(diskPath(p)), "method": "GET", "templated": false})
+}
+
+// publicPathLocked resolves rel inside the resource published under key to
+// a disk path.
+func (s *Server) publicPathLocked(key, rel string) (string, string, bool) {
+	if key == "" {
+		return "", "", false
+	}
+	for p, n := range s.nodes {
+		if n.publicKey == key {
+			rel = path.Clean("/" + rel)
+			if rel == "/" {
+				return p, rel, true
+			}
+			return path.Join(p, rel), rel, true
+		}
+	}
+	return "", "", false
+}
+
+func (s *Server) servePublicResource(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	p, rel, ok := s.publicPathLocked(q.Get("public_key"), q.Get("path"))
+	n, exists := s.nodes[p]
+	if !ok || !exists {
+		writeError(w, http.StatusNotFound, "DiskNotFoundError")
+		return
+	}
+	public := func(p, rel string, n *node) map[string]any {
+		out := s.resourceLocked(p, n)
+		out["path"] = rel
+		out["public_key"] = q.Get("public_key")
+		return out
+	}
+	out := public(p, rel, n)
+	if n.dir {
+		limit := atoiDefault(q.Get("limit"), 20)
+		offset := atoiDefault(q.Get("offset"), 0)
+		children := s.childrenLocked(p)
+		items := []map[string]any{}
+		for i := offset; i < len(children) && i < offset+limit; i++ {
+			items = append(items, public(children[i], path.Join(rel, path.Base(children[i])), s.nodes[children[i]]))
+		}
+		out["_embedded"] = map[string]any{"public_key": q.Get("public_key"), "path": rel, "limit": limit, "offset": offset, "total": len(children), "items": items}
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) servePublicSettings(w http.ResponseWriter, r *http.Request) {
+	n, ok := s.nodes[Normalize(r.URL.Query().Get("path"))]
+	if !ok || n.publicKey == "" {
+		writeError(w, http.StatusNotFound, "DiskNotFoundError")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, n.settings)
+	case http.MethodPatch:
+		var patch map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeError(w, http.StatusBadRequest, "FieldValidationError")
+			return
+		}
+		for k, v := range patch {
+			n.settings[k] = v
+		}
+		writeJSON(w, http.StatusOK, n.settings)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowedError")
+	}
+}
+
+func (s *Server) serveTrash(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	p := TrashPath(q.Get("path"))
+	switch r.Method {
+	case http.MethodGet:
+		if p == "/" {
+			names := make([]string, 0, len(s.trash))
+			for name := range s.trash {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			limit := atoiDefault(q.Get("limit"), 20)
+			offset := atoiDefault(q.Get("offset"), 0)
+			items := []map[string]any{}
+			for i := offset; i < len(names) && i < offset+limit; i++ {
+				items = append(items, s.trashResourceLocked(names[i], s.trash[names[i]]))
+			}
+			writeJSON(w, http.StatusOK, map[string]any{
+				"path": "trash:/", "name": "trash", "type": "dir",
+				"_embedded": map[string]any{"path": "trash:/", "limit": limit, "offset": offset, "total": len(names), "items": items},
+			})
+			return
+		}
+		e, ok := s.trash[p]
+		if !ok {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.trashResourceLocked(p, e))
+	case http.MethodDelete:
+		if q.Get("path") == "" || p == "/" {
+			s.trash = make(map[string]*trashEntry)
+		} else if _, ok := s.trash[p]; ok {
+			delete(s.trash, p)
+		} else {
+			writeError(w, http.StatusNotFound, "DiskNotFoundError")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowedError")
+	}
+}
+
+func (s *Server) serveRestore(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	p := TrashPath(q.Get("path"))
+	e, ok := s.trash[p]
+	if !ok {
+		writeError(w, http.StatusNotFound, "DiskNotFoundError")
+		return
+	}
+	dest := e.origin
+	if name := q.Get("name"); name != "" {
+		dest = path.Join(path.Dir(e.origin), name)
+	}
+	if _, exists := s.nodes[dest]; exists {
+		if q.Get("overwrite") != "true" {
+			writeError(w, http.StatusConflict, "DiskResourceAlreadyExistsError")
+			return
+		}
+		s.removeLocked(dest)
+	}
+	s.mkdirAllLocked(path.Dir(dest))
+	for rel, n := range e.nodes {
+		s.nodes[dest+rel] = n
+	}
+	s.revision++
+	delete(s.trash, p)
+	s.respondAction(w, dest)
+}
+
+func (s *Server) trashResourceLocked(name string, e *trashEntry) map[string]any {
+	out := s.resourceLocked(e.origin, e.nodes[""])
+	out["path"] = "trash:" + name
+	out["name"] = path.Base(e.origin)
+	out["origin_path"] = diskPath(e.origin)
+	out["deleted"] = e.deleted.UTC().Format(time.RFC3339)
+	return out
+}
+
+func (s *Server) serveOperation(w http.ResponseWriter, id string) {
+	status, ok := s.operations[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "OperationNotFoundError")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": status})
+}
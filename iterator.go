@@ -0,0 +1,122 @@
+package yadisk
+
+import (
+	"context"
+	"strings"
+)
+
+const filesPageSize = 1000
+
+// FileIterator pages through Resources.ListAllFiles. Typical use:
+//
+//	it := client.Resources.IterateFiles(ctx, yadisk.FlatFilesRequest{})
+//	for it.Next() {
+//		r := it.Resource()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type FileIterator struct {
+	ctx    context.Context
+	s      *ResourcesService
+	req    FlatFilesRequest
+	limit  int
+	offset int
+
+	page []Resource
+	pos  int
+	cur  Resource
+	done bool
+	err  error
+}
+
+// IterateFiles lists every file on the disk, fetching pages lazily. The
+// page size is req.Limit, or 1000 when unset; req.Offset sets where to
+// start.
+func (s *ResourcesService) IterateFiles(ctx context.Context, req FlatFilesRequest) *FileIterator {
+	it := &FileIterator{ctx: ctx, s: s, req: req, limit: filesPageSize}
+	if req.Limit != nil && *req.Limit > 0 {
+		it.limit = *req.Limit
+	}
+	if req.Offset != nil {
+		it.offset = *req.Offset
+	}
+	return it
+}
+
+func (it *FileIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.cur = it.page[it.pos]
+	it.pos++
+	return true
+}
+
+func (it *FileIterator) fetch() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+	if it.s.client.scope != nil {
+		return it.fetchScoped()
+	}
+	req := it.req
+	limit, offset := it.limit, it.offset
+	req.Limit, req.Offset = &limit, &offset
+	list, err := it.s.ListAllFiles(it.ctx, req)
+	if err != nil {
+		return err
+	}
+	it.page, it.pos = list.Items, 0
+	it.offset += len(list.Items)
+	if len(list.Items) < it.limit {
+		it.done = true
+	}
+	return nil
+}
+
+// fetchScoped lists the files of a scoped client by walking its root, as
+// the flat listing covers the whole disk. The result is not sorted.
+func (it *FileIterator) fetchScoped() error {
+	var files []Resource
+	err := it.s.Walk(it.ctx, "disk:/", func(r Resource) error {
+		if r.Type != "dir" && (it.req.MediaType == "" || mediaTypeIn(r.MediaType, it.req.MediaType)) {
+			files = append(files, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if it.offset < len(files) {
+		it.page = files[it.offset:]
+	}
+	it.pos, it.done = 0, true
+	return nil
+}
+
+func mediaTypeIn(mediaType, list string) bool {
+	for _, t := range strings.Split(list, ",") {
+		if strings.TrimSpace(t) == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource returns the file Next advanced to.
+func (it *FileIterator) Resource() Resource {
+	return it.cur
+}
+
+func (it *FileIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,274 @@
+package yadisk
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	lockOwnerProperty   = "lock_owner"
+	lockExpiresProperty = "lock_expires"
+)
+
+var (
+	// ErrLocked is returned by TryLock while another owner holds a live
+	// lease.
+	ErrLocked = errors.New("resource is locked")
+	// ErrLockLost is returned by Renew and Release when the lock file was
+	// taken over or removed.
+	ErrLockLost = errors.New("lock is no longer held")
+)
+
+// Lock is a lease on a lock file. The lease ends at Expires unless it is
+// renewed; after that another owner may take the lock over. Leases compare
+// expiry times written by other clients with the local clock, so ttl should
+// be well above the clock skew between them.
+type Lock struct {
+	client *Client
+	path   string
+	owner  string
+	md5    string
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	expires time.Time
+}
+
+// Lock acquires the lock file at p, waiting with the client's retry backoff
+// while another owner holds it, until ctx is done.
+func (s *ResourcesService) Lock(ctx context.Context, p string, ttl time.Duration) (_ *Lock, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.Lock")
+	defer func() { end(err) }()
+
+	for attempt := 1; ; attempt++ {
+		l, err := s.tryLock(ctx, p, ttl)
+		if !errors.Is(err, ErrLocked) {
+			return l, err
+		}
+		if err := sleepWithContext(ctx, s.client.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// TryLock acquires the lock file at p or fails with ErrLocked. The file is
+// created with an exclusive upload; a file whose lease has expired is
+// deleted and created again. Missing parent folders are created.
+func (s *ResourcesService) TryLock(ctx context.Context, p string, ttl time.Duration) (_ *Lock, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.TryLock")
+	defer func() { end(err) }()
+
+	return s.tryLock(ctx, p, ttl)
+}
+
+func (s *ResourcesService) tryLock(ctx context.Context, p string, ttl time.Duration) (*Lock, error) {
+	if p == "" {
+		return nil, errors.New("path is required")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("lock ttl must be positive")
+	}
+	owner, err := newLockOwner()
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum([]byte(owner))
+	// A lock file without an expiry is stale ttl after it was modified,
+	// which is no earlier than now.
+	l := &Lock{client: s.client, path: p, owner: owner, md5: hex.EncodeToString(sum[:]), ttl: ttl, expires: time.Now().Add(ttl)}
+
+	created, err := l.create(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		if err := l.takeOver(ctx); err != nil {
+			return nil, err
+		}
+		if created, err = l.create(ctx); err != nil {
+			return nil, err
+		}
+		if !created {
+			return nil, fmt.Errorf("%w: %s", ErrLocked, p)
+		}
+	}
+	if err := l.Renew(ctx); err != nil {
+		if errors.Is(err, ErrLockLost) {
+			return nil, fmt.Errorf("%w: %s", ErrLocked, p)
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+// create uploads the lock file unless it exists, reporting whether this
+// lock now owns it. The uploader checks the missing overwrite flag again when
+// the upload completes, so of two clients holding links only the first to
+// finish creates the file; the other's upload fails with a conflict and
+// loses.
+func (l *Lock) create(ctx context.Context) (bool, error) {
+	s := l.client.Resources
+	overwrite := false
+	link, err := l.client.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: l.path, Overwrite: &overwrite})
+	switch apiErrorCode(err) {
+	case "":
+		if err != nil {
+			return false, err
+		}
+	case codeResourceExists:
+		return false, nil
+	case codeParentMissing:
+		if err := s.MkdirAll(ctx, path.Dir(strings.TrimSuffix(l.path, "/"))); err != nil {
+			return false, err
+		}
+		return l.create(ctx)
+	default:
+		return false, err
+	}
+	if _, err := l.client.Uploads.UploadByLink(ctx, link, strings.NewReader(l.owner)); err != nil {
+		if isConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	// Confirm that the content in the file is ours; Renew then writes the
+	// lease under the same MD5 precondition.
+	err = l.client.checkPrecondition(ctx, l.path, &Precondition{MD5: l.md5})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// takeOver deletes the lock file if its lease has expired and fails with
+// ErrLocked otherwise. A file without an expiry, left by an owner that
+// failed between creating and renewing it, is stale once it is older than
+// l's ttl. Renewals only rewrite properties and leave the MD5 unchanged, so
+// the lease is read again right before the delete to catch one that landed
+// after the first read.
+func (l *Lock) takeOver(ctx context.Context) error {
+	r, found, err := l.expired(ctx)
+	if err != nil || !found {
+		return err
+	}
+	if r, found, err = l.expired(ctx); err != nil || !found {
+		return err
+	}
+	permanently := true
+	_, err = l.client.Resources.Delete(ctx, DeleteResourceRequest{Path: l.path, MD5: r.MD5, Permanently: &permanently})
+	if isNotFound(err) || errors.Is(err, ErrPreconditionFailed) {
+		// Someone else took it over first; the exclusive create decides.
+		return nil
+	}
+	return err
+}
+
+// expired reads the lock file and fails with ErrLocked while its lease is
+// live. found is false if the file does not exist.
+func (l *Lock) expired(ctx context.Context) (r Resource, found bool, err error) {
+	q := url.Values{}
+	addString(q, "path", l.path)
+	addCSV(q, "fields", []string{"md5", "modified", "custom_properties"})
+	if _, err := l.client.doJSON(ctx, http.MethodGet, "/disk/resources", q, nil, &r, http.StatusOK); err != nil {
+		if isNotFound(err) {
+			return r, false, nil
+		}
+		return r, false, err
+	}
+	expires := r.Modified.Time.Add(l.ttl)
+	if v, ok := r.CustomProperties[lockExpiresProperty].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			expires = t
+		}
+	}
+	if time.Now().Before(expires) {
+		owner, _ := r.CustomProperties[lockOwnerProperty].(string)
+		return r, true, fmt.Errorf("%w: %s is held by %s until %s", ErrLocked, l.path, owner, expires.Format(time.RFC3339))
+	}
+	return r, true, nil
+}
+
+// Path returns the lock file path.
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Owner returns the random ID stored in the lock file.
+func (l *Lock) Owner() string {
+	return l.owner
+}
+
+func (l *Lock) Expires() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.expires
+}
+
+// Renew extends the lease by the lock's ttl from now. It fails with
+// ErrLockLost if the lock file no longer belongs to l, or if the lease has
+// already expired, since another owner may have taken the lock over.
+func (l *Lock) Renew(ctx context.Context) (err error) {
+	ctx, end := l.client.startCall(ctx, "Lock.Renew")
+	defer func() { end(err) }()
+
+	if time.Now().After(l.Expires()) {
+		return fmt.Errorf("%w: %s: lease expired", ErrLockLost, l.path)
+	}
+	expires := time.Now().Add(l.ttl)
+	_, err = l.client.Resources.UpdateMeta(ctx, ResourceUpdateRequest{
+		Path: l.path,
+		CustomProperties: map[string]any{
+			lockOwnerProperty:   l.owner,
+			lockExpiresProperty: expires.UTC().Format(time.RFC3339Nano),
+		},
+		Fields:  []string{"path"},
+		IfMatch: &Precondition{MD5: l.md5},
+	})
+	if errors.Is(err, ErrPreconditionFailed) {
+		return fmt.Errorf("%w: %s", ErrLockLost, l.path)
+	}
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	l.expires = expires
+	l.mu.Unlock()
+	return nil
+}
+
+// Release deletes the lock file. It fails with ErrLockLost if the file no
+// longer belongs to l, in which case it is left alone.
+func (l *Lock) Release(ctx context.Context) (err error) {
+	ctx, end := l.client.startCall(ctx, "Lock.Release")
+	defer func() { end(err) }()
+
+	permanently := true
+	_, err = l.client.Resources.Delete(ctx, DeleteResourceRequest{Path: l.path, MD5: l.md5, Permanently: &permanently})
+	if isNotFound(err) || errors.Is(err, ErrPreconditionFailed) {
+		return fmt.Errorf("%w: %s", ErrLockLost, l.path)
+	}
+	if err == nil {
+		l.mu.Lock()
+		l.expires = time.Time{}
+		l.mu.Unlock()
+	}
+	return err
+}
+
+func newLockOwner() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
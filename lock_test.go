@@ -0,0 +1,221 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func TestLockLifecycle(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+
+	a, err := client.Resources.TryLock(ctx, "disk:/locks/job", time.Minute)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	if !srv.Exists("disk:/locks/job") || time.Until(a.Expires()) <= 0 {
+		t.Fatalf("lock file missing or expired: %v", a.Expires())
+	}
+	if _, err := client.Resources.TryLock(ctx, "disk:/locks/job", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second lock = %v", err)
+	}
+	if err := a.Renew(ctx); err != nil {
+		t.Fatalf("renew: %v", err)
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if srv.Exists("disk:/locks/job") || srv.Trashed("disk:/locks/job") {
+		t.Fatal("lock file not removed")
+	}
+
+	b, err := client.Resources.TryLock(ctx, "disk:/locks/job", time.Minute)
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+	released := make(chan error, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		released <- b.Release(ctx)
+	}()
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	c, err := client.Resources.Lock(waitCtx, "disk:/locks/job", time.Minute)
+	if err != nil {
+		t.Fatalf("blocking lock: %v", err)
+	}
+	if err := <-released; err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if c.Owner() == b.Owner() {
+		t.Fatal("owners are not unique")
+	}
+}
+
+func TestLockStaleTakeover(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+
+	a, err := client.Resources.TryLock(ctx, "disk:/job.lock", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	b, err := client.Resources.TryLock(ctx, "disk:/job.lock", time.Minute)
+	if err != nil {
+		t.Fatalf("takeover: %v", err)
+	}
+	if err := a.Renew(ctx); !errors.Is(err, ErrLockLost) {
+		t.Fatalf("renew after takeover = %v", err)
+	}
+	if err := a.Release(ctx); !errors.Is(err, ErrLockLost) {
+		t.Fatalf("release after takeover = %v", err)
+	}
+	if !srv.Exists("disk:/job.lock") {
+		t.Fatal("stale owner removed the new lock")
+	}
+	if err := b.Release(ctx); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+
+	// A lock file left without an expiry is stale once older than the ttl.
+	srv.PutFile("disk:/orphan.lock", []byte("crashed"), time.Now().Add(-time.Hour))
+	if _, err := client.Resources.TryLock(ctx, "disk:/orphan.lock", time.Minute); err != nil {
+		t.Fatalf("orphan takeover: %v", err)
+	}
+	srv.PutFile("disk:/fresh.lock", []byte("starting"), time.Now())
+	if _, err := client.Resources.TryLock(ctx, "disk:/fresh.lock", time.Minute); !errors.Is(err, ErrLocked) {
+		t.Fatalf("fresh lock without expiry = %v", err)
+	}
+}
+
+func TestTryLockInterleavedUploads(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+	srv.Mkdir("disk:/locks")
+
+	held := make(chan struct{})
+	release := make(chan struct{})
+	// b's upload is held until a has taken the lock, so both got their
+	// upload links while the lock file did not exist.
+	b, err := NewClient(WithOAuthToken("token"), WithBaseURL(srv.URL), WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			if r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/upload/") {
+				close(held)
+				<-release
+			}
+			return next(r)
+		}
+	}))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	a, err := NewClient(WithOAuthToken("token"), WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	bErr := make(chan error, 1)
+	go func() {
+		_, err := b.Resources.TryLock(ctx, "disk:/locks/job", time.Minute)
+		bErr <- err
+	}()
+	<-held
+	lock, err := a.Resources.TryLock(ctx, "disk:/locks/job", time.Minute)
+	close(release)
+	if err != nil {
+		t.Fatalf("first lock: %v", err)
+	}
+	if err := <-bErr; !errors.Is(err, ErrLocked) {
+		t.Fatalf("interleaved lock = %v", err)
+	}
+	if err := lock.Renew(ctx); err != nil {
+		t.Fatalf("renew after interleaved upload: %v", err)
+	}
+}
+
+func TestLockRenewRacesTakeover(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	ctx := context.Background()
+
+	a, err := NewClient(WithOAuthToken("token"), WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	lock, err := a.Resources.TryLock(ctx, "disk:/job.lock", time.Minute)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	// Stand in for a lease that looks expired to the taker, as it would
+	// with clock skew, while the holder still renews in time.
+	if _, err := a.Resources.UpdateMeta(ctx, ResourceUpdateRequest{
+		Path:             "disk:/job.lock",
+		CustomProperties: map[string]any{lockExpiresProperty: time.Now().Add(-time.Second).UTC().Format(time.RFC3339Nano)},
+	}); err != nil {
+		t.Fatalf("update meta: %v", err)
+	}
+
+	held := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	// b is held after it first reads the expired lease, before it deletes.
+	b, err := NewClient(WithOAuthToken("token"), WithBaseURL(srv.URL), WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+		return func(r *http.Request) (*http.Response, error) {
+			resp, err := next(r)
+			if r.Method == http.MethodGet && strings.Contains(r.URL.Query().Get("fields"), "custom_properties") {
+				once.Do(func() {
+					close(held)
+					<-release
+				})
+			}
+			return resp, err
+		}
+	}))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	bErr := make(chan error, 1)
+	go func() {
+		_, err := b.Resources.TryLock(ctx, "disk:/job.lock", time.Minute)
+		bErr <- err
+	}()
+	<-held
+	renewErr := lock.Renew(ctx)
+	close(release)
+	if renewErr != nil {
+		t.Fatalf("renew: %v", renewErr)
+	}
+	if err := <-bErr; !errors.Is(err, ErrLocked) {
+		t.Fatalf("takeover during renew = %v", err)
+	}
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+}
+
+func TestLockRenewAfterExpiry(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+
+	lock, err := client.Resources.TryLock(ctx, "disk:/job.lock", 20*time.Millisecond)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if err := lock.Renew(ctx); !errors.Is(err, ErrLockLost) {
+		t.Fatalf("renew after expiry = %v", err)
+	}
+	if !srv.Exists("disk:/job.lock") {
+		t.Fatal("lock file removed")
+	}
+}
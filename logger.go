@@ -0,0 +1,172 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LogLevels sets the level each kind of event is logged at.
+type LogLevels struct {
+	Request      slog.Level
+	RequestError slog.Level
+	Retry        slog.Level
+	Transfer     slog.Level
+	Operation    slog.Level
+}
+
+func DefaultLogLevels() LogLevels {
+	return LogLevels{
+		Request:      slog.LevelDebug,
+		RequestError: slog.LevelWarn,
+		Retry:        slog.LevelWarn,
+		Transfer:     slog.LevelInfo,
+		Operation:    slog.LevelDebug,
+	}
+}
+
+// WithLogger emits a structured record for every HTTP attempt, retry,
+// transfer start and end, and watched operation event. The OAuth token is
+// never logged, and signed upload/download hrefs are reduced to their scheme
+// and host, in URLs and error messages alike.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+func WithLogLevels(levels LogLevels) Option {
+	return func(c *config) error {
+		c.logLevels = levels
+		return nil
+	}
+}
+
+type clientLogger struct {
+	l      *slog.Logger
+	levels LogLevels
+}
+
+func (l *clientLogger) request(ctx context.Context, req *http.Request, resp *http.Response, attempt int, duration time.Duration, signed bool, err error) {
+	if l == nil {
+		return
+	}
+	level := l.levels.Request
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL, signed)),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", duration),
+	}
+	if req.ContentLength > 0 {
+		attrs = append(attrs, slog.Int64("bytes_out", req.ContentLength))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if id := firstNonEmpty(resp.Header.Get("X-Request-Id"), resp.Header.Get("X-YaRequestId")); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		if resp.ContentLength >= 0 {
+			attrs = append(attrs, slog.Int64("bytes_in", resp.ContentLength))
+		}
+		if resp.StatusCode >= 400 {
+			level = l.levels.RequestError
+		}
+	}
+	if err != nil {
+		level = l.levels.RequestError
+		attrs = append(attrs, slog.String("error", redactError(err, signed)))
+	}
+	l.l.LogAttrs(ctx, level, "yadisk request", attrs...)
+}
+
+func (l *clientLogger) retry(ctx context.Context, e RetryEvent) {
+	if l == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", e.Method),
+		slog.String("url", e.URL),
+		slog.Int("attempt", e.Attempt),
+		slog.Duration("backoff", e.NextBackoff),
+	}
+	if e.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", e.StatusCode))
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("error", e.Err.Error()))
+	}
+	l.l.LogAttrs(ctx, l.levels.Retry, "yadisk retry", attrs...)
+}
+
+func (l *clientLogger) transfer(p TransferProgress) {
+	if l == nil || (!p.Done && p.BytesDone > 0) {
+		return
+	}
+	msg := "yadisk transfer started"
+	attrs := []slog.Attr{
+		slog.String("direction", string(p.Direction)),
+		slog.Int64("total", p.Total),
+	}
+	if p.Done {
+		msg = "yadisk transfer finished"
+		attrs = append(attrs, slog.Int64("bytes", p.BytesDone), slog.Float64("rate", p.Rate))
+		if p.Chunk > 0 {
+			attrs = append(attrs, slog.Int("chunks", p.Chunk))
+		}
+		if p.Err != nil {
+			// Transfers always go to signed hrefs.
+			attrs = append(attrs, slog.String("error", redactError(p.Err, true)))
+		}
+	}
+	l.l.LogAttrs(context.Background(), l.levels.Transfer, msg, attrs...)
+}
+
+func (l *clientLogger) operation(e OperationEvent) {
+	if l == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("operation_id", e.Ref.ID),
+		slog.String("status", e.Status),
+		slog.Bool("done", e.Done),
+		slog.Duration("elapsed", e.Elapsed),
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("error", e.Err.Error()))
+	}
+	l.l.LogAttrs(context.Background(), l.levels.Operation, "yadisk operation", attrs...)
+}
+
+// redactURL renders u for logs. Signed upload and download hrefs carry
+// signatures and tokens in both path and query, so only their scheme and
+// host are kept.
+func redactURL(u *url.URL, signed bool) string {
+	if u == nil {
+		return ""
+	}
+	if !signed {
+		return u.String()
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+}
+
+// redactError renders err for logs. The text of a *url.Error embeds the
+// whole href, so for signed hrefs it is replaced with the redacted form.
+func redactError(err error, signed bool) string {
+	msg := err.Error()
+	var urlErr *url.Error
+	if !signed || !errors.As(err, &urlErr) || urlErr.URL == "" {
+		return msg
+	}
+	redacted := "REDACTED"
+	if u, err := url.Parse(urlErr.URL); err == nil {
+		redacted = redactURL(u, true)
+	}
+	return strings.ReplaceAll(msg, urlErr.URL, redacted)
+}
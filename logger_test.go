@@ -0,0 +1,204 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("decode log line %q: %v", line, err)
+		}
+		out = append(out, rec)
+	}
+	return out
+}
+
+func (b *logBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newLoggedClient(t *testing.T, baseURL string, opts ...Option) (*Client, *logBuffer) {
+	t.Helper()
+	buf := &logBuffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	opts = append([]Option{WithOAuthToken("secret-token"), WithBaseURL(baseURL), WithLogger(logger)}, opts...)
+	client, err := NewClient(opts...)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client, buf
+}
+
+func TestLoggerRequestsAndRetries(t *testing.T) {
+	var attempts int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-42")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(w, `{"error":"Unavailable"}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"total_space":10}`)
+	}))
+	t.Cleanup(ts.Close)
+
+	client, buf := newLoggedClient(t, ts.URL)
+	client.retry = RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	if _, err := client.Disk.Get(context.Background(), DiskGetRequest{}); err != nil {
+		t.Fatalf("disk get: %v", err)
+	}
+
+	recs := buf.records(t)
+	if len(recs) != 3 {
+		t.Fatalf("records = %v", recs)
+	}
+	first, retry, second := recs[0], recs[1], recs[2]
+	if first["msg"] != "yadisk request" || first["level"] != "WARN" || first["status"] != float64(503) || first["attempt"] != float64(1) {
+		t.Fatalf("first attempt = %v", first)
+	}
+	if first["method"] != http.MethodGet || first["request_id"] != "req-42" {
+		t.Fatalf("first attempt = %v", first)
+	}
+	if retry["msg"] != "yadisk retry" || retry["status"] != float64(503) {
+		t.Fatalf("retry = %v", retry)
+	}
+	if second["level"] != "DEBUG" || second["status"] != float64(200) || second["attempt"] != float64(2) {
+		t.Fatalf("second attempt = %v", second)
+	}
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Fatal("token leaked into logs")
+	}
+}
+
+func TestLoggerTransfersRedactSignedHrefs(t *testing.T) {
+	_, srv := newFakeDiskClient(t)
+	srv.PutFile("disk:/a.txt", []byte("hello"), time.Now())
+	client, buf := newLoggedClient(t, srv.URL)
+
+	rc, err := client.Uploads.OpenDownload(context.Background(), DownloadURLRequest{Path: "disk:/a.txt"})
+	if err != nil {
+		t.Fatalf("open download: %v", err)
+	}
+	if _, err := io.ReadAll(rc); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	_ = rc.Close()
+
+	var redacted, started, finished bool
+	for _, rec := range buf.records(t) {
+		switch rec["msg"] {
+		case "yadisk request":
+			u := rec["url"].(string)
+			if strings.HasPrefix(u, srv.URL+"/download") {
+				t.Fatalf("signed href not redacted: %s", u)
+			}
+			redacted = redacted || u == srv.URL
+		case "yadisk transfer started":
+			started = true
+		case "yadisk transfer finished":
+			finished = rec["bytes"] == float64(5) && rec["direction"] == string(TransferDownload)
+		}
+	}
+	if !redacted {
+		t.Fatal("signed href not logged")
+	}
+	if !started || !finished {
+		t.Fatalf("transfer records missing: %s", buf.String())
+	}
+}
+
+func TestRedactURLDropsSignedPath(t *testing.T) {
+	u, err := url.Parse("https://downloader.disk.yandex.ru/disk/3f1c0a9e-token-in-path/6650c2a7/file.txt?uid=1&hash=abc&sign=xyz")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := redactURL(u, true); got != "https://downloader.disk.yandex.ru" {
+		t.Fatalf("signed = %s", got)
+	}
+	if got := redactURL(u, false); got != u.String() {
+		t.Fatalf("unsigned = %s", got)
+	}
+}
+
+func TestLoggerLevels(t *testing.T) {
+	_, srv := newFakeDiskClient(t)
+	levels := DefaultLogLevels()
+	levels.Request = slog.LevelDebug - 4
+	client, buf := newLoggedClient(t, srv.URL, WithLogLevels(levels))
+	if _, err := client.Disk.Get(context.Background(), DiskGetRequest{}); err != nil {
+		t.Fatalf("disk get: %v", err)
+	}
+	if out := buf.String(); out != "" {
+		t.Fatalf("expected request below handler level to be dropped, got %s", out)
+	}
+}
+
+type failingTransport struct {
+	next http.RoundTripper
+	fail func(*http.Request) bool
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.fail(req) {
+		return nil, errors.New("connection reset")
+	}
+	return t.next.RoundTrip(req)
+}
+
+func TestLoggerRedactsSignedHrefsInErrors(t *testing.T) {
+	_, srv := newFakeDiskClient(t)
+	srv.PutFile("disk:/a.txt", []byte("hello"), time.Now())
+	httpClient := &http.Client{Transport: failingTransport{
+		next: http.DefaultTransport,
+		fail: func(r *http.Request) bool { return r.URL.Path == "/download" },
+	}}
+	client, buf := newLoggedClient(t, srv.URL, WithHTTPClient(httpClient))
+
+	_, err := client.Uploads.OpenDownload(context.Background(), DownloadURLRequest{Path: "disk:/a.txt"})
+	if err == nil || !strings.Contains(err.Error(), "/download?path=") {
+		t.Fatalf("open download = %v", err)
+	}
+	logged := buf.String()
+	if strings.Contains(logged, srv.URL+"/download") {
+		t.Fatalf("signed href leaked into logs: %s", logged)
+	}
+	if !strings.Contains(logged, "connection reset") {
+		t.Fatalf("transfer error not logged: %s", logged)
+	}
+}
@@ -0,0 +1,32 @@
+package yadisk
+
+import "net/http"
+
+// RoundTripFunc sends a single HTTP attempt.
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of every HTTP attempt, both API requests and
+// transfers to signed upload and download hrefs. A middleware may modify the
+// request, replace the response or return without calling next. Retries,
+// hooks and logging happen outside the middleware chain.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware appends middleware to the client's chain. The first
+// middleware given is the outermost.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *config) error {
+		for _, m := range mw {
+			if m != nil {
+				c.middleware = append(c.middleware, m)
+			}
+		}
+		return nil
+	}
+}
+
+func chainMiddleware(base RoundTripFunc, mw []Middleware) RoundTripFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		base = mw[i](base)
+	}
+	return base
+}
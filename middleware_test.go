@@ -0,0 +1,104 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMiddlewareOrderAndHeaderInjection(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name+" in")
+				req.Header.Set("X-Team", name)
+				resp, err := next(req)
+				order = append(order, name+" out")
+				return resp, err
+			}
+		}
+	}
+
+	var team string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		team = r.Header.Get("X-Team")
+		w.Header().Set("Content-Type", "application/json")
+		mustFprint(t, w, `{}`)
+	})
+	client.transport.roundTrip = chainMiddleware(client.transport.roundTrip, []Middleware{trace("outer"), trace("inner")})
+
+	if _, err := client.Disk.Get(context.Background(), DiskGetRequest{}); err != nil {
+		t.Fatalf("disk get: %v", err)
+	}
+	want := []string{"outer in", "inner in", "inner out", "outer out"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %q want %q", order, want)
+	}
+	if team != "inner" {
+		t.Fatalf("header = %q", team)
+	}
+}
+
+func TestMiddlewareShortCircuitAndRetries(t *testing.T) {
+	var served, calls int32
+	faults := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return nil, errors.New("injected fault")
+			}
+			return next(req)
+		}
+	}
+	cached := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/download") {
+				return &http.Response{
+					StatusCode:    http.StatusOK,
+					Header:        http.Header{},
+					Body:          io.NopCloser(bytes.NewReader([]byte("cached"))),
+					ContentLength: 6,
+					Request:       req,
+				}, nil
+			}
+			return next(req)
+		}
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&served, 1)
+		w.Header().Set("Content-Type", "application/json")
+		mustFprint(t, w, `{"href":"http://`+r.Host+`/download?sig=x","method":"GET"}`)
+	}))
+	t.Cleanup(ts.Close)
+	client, err := NewClient(
+		WithOAuthToken("token"),
+		WithBaseURL(ts.URL),
+		WithMiddleware(faults, cached),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	rc, err := client.Uploads.OpenDownload(context.Background(), DownloadURLRequest{Path: "disk:/a.txt"})
+	if err != nil {
+		t.Fatalf("open download: %v", err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil || string(data) != "cached" {
+		t.Fatalf("data = %q err = %v", data, err)
+	}
+	if served != 1 || calls != 3 {
+		t.Fatalf("served = %d middleware calls = %d", served, calls)
+	}
+}
@@ -0,0 +1,77 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrNotDirectory is returned by MkdirAll when a file is in the way.
+var ErrNotDirectory = errors.New("path exists and is not a directory")
+
+const (
+	codeDirectoryExists = "DiskPathPointsToExistentDirectoryError"
+	codeResourceExists  = "DiskResourceAlreadyExistsError"
+	codeParentMissing   = "DiskPathDoesntExistsError"
+)
+
+// MkdirAll creates the folder p and any missing parents. A folder that
+// already exists, including one created concurrently by another caller, is
+// not an error; a file at p or at one of its parents fails with
+// ErrNotDirectory.
+func (s *ResourcesService) MkdirAll(ctx context.Context, p string) (err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.MkdirAll")
+	defer func() { end(err) }()
+
+	if p == "" {
+		return errors.New("path is required")
+	}
+	parsed, err := ParsePath(p)
+	if err != nil {
+		return err
+	}
+	return s.mkdirAll(ctx, parsed)
+}
+
+func (s *ResourcesService) mkdirAll(ctx context.Context, p Path) error {
+	if p.IsRoot() {
+		return nil
+	}
+	err := s.mkdir(ctx, p)
+	if apiErrorCode(err) == codeParentMissing {
+		if err := s.mkdirAll(ctx, p.Dir()); err != nil {
+			return err
+		}
+		err = s.mkdir(ctx, p)
+	}
+	return err
+}
+
+// mkdir creates p, treating an existing folder as success.
+func (s *ResourcesService) mkdir(ctx context.Context, p Path) error {
+	defer s.client.cache.invalidate(p.String())
+
+	q := url.Values{}
+	addString(q, "path", p.String())
+	_, err := s.client.doJSON(ctx, http.MethodPut, "/disk/resources", q, nil, nil, http.StatusCreated)
+	switch apiErrorCode(err) {
+	case "":
+		return err
+	case codeDirectoryExists:
+		return nil
+	case codeResourceExists:
+		return fmt.Errorf("%s: %w", p, ErrNotDirectory)
+	default:
+		return err
+	}
+}
+
+func apiErrorCode(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMkdirAll(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+
+	if err := client.Resources.MkdirAll(ctx, "disk:/a/b/c"); err != nil {
+		t.Fatalf("mkdir all: %v", err)
+	}
+	if !srv.Exists("disk:/a/b/c") {
+		t.Fatal("folder not created")
+	}
+	if err := client.Resources.MkdirAll(ctx, "/a/b"); err != nil {
+		t.Fatalf("existing folder: %v", err)
+	}
+	if err := client.Resources.MkdirAll(ctx, "disk:/"); err != nil {
+		t.Fatalf("root: %v", err)
+	}
+
+	srv.PutFile("disk:/file", []byte("f"), time.Now())
+	for _, p := range []string{"disk:/file", "disk:/file/sub"} {
+		if err := client.Resources.MkdirAll(ctx, p); !errors.Is(err, ErrNotDirectory) {
+			t.Fatalf("mkdir %s = %v", p, err)
+		}
+	}
+}
+
+func TestMkdirAllConcurrent(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- client.Resources.MkdirAll(ctx, fmt.Sprintf("disk:/shared/tree/n%d", i%4))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent mkdir: %v", err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		if !srv.Exists(fmt.Sprintf("disk:/shared/tree/n%d", i)) {
+			t.Fatalf("n%d missing", i)
+		}
+	}
+}
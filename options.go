@@ -2,6 +2,7 @@ package yadisk
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
@@ -23,6 +24,15 @@ type config struct {
 	retryPolicy RetryPolicy
 	hooks       Hooks
 	worker      WorkerConfig
+	logger      *slog.Logger
+	middleware  []Middleware
+	metaCache   *MetaCacheConfig
+	quotaCheck  *QuotaCheckConfig
+	scope       string
+	logLevels   LogLevels
+
+	uploadLimit   int64
+	downloadLimit int64
 }
 
 type RetryPolicy struct {
@@ -68,6 +78,7 @@ func defaultConfig() (*config, error) {
 		httpClient:  http.DefaultClient,
 		retryPolicy: DefaultRetryPolicy(),
 		worker:      DefaultWorkerConfig(),
+		logLevels:   DefaultLogLevels(),
 	}, nil
 }
 
@@ -129,6 +140,19 @@ func WithHooks(hooks Hooks) Option {
 	}
 }
 
+// WithBandwidthLimit caps the combined throughput of all uploads and
+// downloads made by the client, in bytes per second. Zero means unlimited.
+func WithBandwidthLimit(upload, download int64) Option {
+	return func(c *config) error {
+		if upload < 0 || download < 0 {
+			return errors.New("bandwidth limits must be >= 0")
+		}
+		c.uploadLimit = upload
+		c.downloadLimit = download
+		return nil
+	}
+}
+
 func WithWorkerConfig(cfg WorkerConfig) Option {
 	return func(c *config) error {
 		if cfg.PollInterval <= 0 || cfg.MaxInterval <= 0 {
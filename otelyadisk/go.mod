@@ -0,0 +1,24 @@
+module github.com/grixate/yandex-disk-go-v2/otelyadisk
+
+go 1.22
+
+require (
+	github.com/grixate/yandex-disk-go-v2 v0.0.0
+	go.opentelemetry.io/otel v1.32.0
+	go.opentelemetry.io/otel/metric v1.32.0
+	go.opentelemetry.io/otel/sdk v1.32.0
+	go.opentelemetry.io/otel/sdk/metric v1.32.0
+	go.opentelemetry.io/otel/trace v1.32.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.4 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/stretchr/testify v1.10.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+)
+
+// Development builds use the root module from this checkout. The replace is
+// ignored by consumers; see "Releasing" in the root README.
+replace github.com/grixate/yandex-disk-go-v2 => ../
@@ -0,0 +1,247 @@
+// Package otelyadisk instruments a yadisk.Client with OpenTelemetry.
+//
+// Every service call such as Resources.Copy gets a span, with child spans
+// for each chunk of a chunked upload and for each HTTP attempt. Metrics
+// cover request latency, retries, bytes transferred and the duration of
+// watched asynchronous operations.
+//
+//	inst, err := otelyadisk.New()
+//	client, err := yadisk.NewClient(append(inst.ClientOptions(), yadisk.WithOAuthToken(token))...)
+//
+// Applications with their own hooks combine them with
+// yadisk.ComposeHooks(inst.Hooks(), own).
+package otelyadisk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	yadisk "github.com/grixate/yandex-disk-go-v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/grixate/yandex-disk-go-v2/otelyadisk"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+type Option func(*config)
+
+// WithTracerProvider defaults to the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider defaults to the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// WithPropagators sets the propagators used to inject trace context into
+// outgoing requests. Defaults to the global propagators.
+func WithPropagators(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = p
+	}
+}
+
+type Instrumentation struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+
+	requestDuration   metric.Float64Histogram
+	retries           metric.Int64Counter
+	transferredBytes  metric.Int64Counter
+	operationDuration metric.Float64Histogram
+}
+
+func New(opts ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	i := &Instrumentation{
+		tracer:      cfg.tracerProvider.Tracer(instrumentationName),
+		propagators: cfg.propagators,
+	}
+	var err error
+	if i.requestDuration, err = meter.Float64Histogram("yadisk.client.request.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duration of HTTP attempts made by the client.")); err != nil {
+		return nil, err
+	}
+	if i.retries, err = meter.Int64Counter("yadisk.client.retries",
+		metric.WithDescription("Requests retried by the client.")); err != nil {
+		return nil, err
+	}
+	if i.transferredBytes, err = meter.Int64Counter("yadisk.client.transfer.bytes",
+		metric.WithUnit("By"), metric.WithDescription("Bytes uploaded and downloaded.")); err != nil {
+		return nil, err
+	}
+	if i.operationDuration, err = meter.Float64Histogram("yadisk.client.operation.duration",
+		metric.WithUnit("s"), metric.WithDescription("Time from watching an asynchronous operation until it finished.")); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// ClientOptions installs the instrumentation's hooks and middleware.
+func (i *Instrumentation) ClientOptions() []yadisk.Option {
+	return []yadisk.Option{yadisk.WithHooks(i.Hooks()), yadisk.WithMiddleware(i.Middleware())}
+}
+
+func (i *Instrumentation) Hooks() yadisk.Hooks {
+	return yadisk.Hooks{
+		OnCall:             i.startCall,
+		OnRetry:            i.recordRetry,
+		OnTransferProgress: i.recordTransfer,
+		OnOperationEvent:   i.recordOperation,
+	}
+}
+
+func (i *Instrumentation) startCall(ctx context.Context, name string) (context.Context, func(error)) {
+	ctx, span := i.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func (i *Instrumentation) recordRetry(e yadisk.RetryEvent) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", e.Method),
+		attribute.String("yadisk.endpoint", e.Endpoint),
+	}
+	if e.StatusCode != 0 {
+		attrs = append(attrs, attribute.Int("http.response.status_code", e.StatusCode))
+	}
+	i.retries.Add(context.Background(), 1, metric.WithAttributes(attrs...))
+}
+
+func (i *Instrumentation) recordTransfer(p yadisk.TransferProgress) {
+	if !p.Done || p.BytesDone == 0 {
+		return
+	}
+	i.transferredBytes.Add(context.Background(), p.BytesDone,
+		metric.WithAttributes(attribute.String("yadisk.transfer.direction", string(p.Direction))))
+}
+
+func (i *Instrumentation) recordOperation(e yadisk.OperationEvent) {
+	if !e.Done {
+		return
+	}
+	i.operationDuration.Record(context.Background(), e.Elapsed.Seconds(),
+		metric.WithAttributes(attribute.String("yadisk.operation.status", e.Status)))
+}
+
+// Middleware records a client span and the request duration for every HTTP
+// attempt and injects the trace context into the outgoing headers.
+func (i *Instrumentation) Middleware() yadisk.Middleware {
+	return func(next yadisk.RoundTripFunc) yadisk.RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return i.roundTrip(req, next)
+		}
+	}
+}
+
+// Transport does what Middleware does for an http.Client that is not used
+// through yadisk. A nil base means http.DefaultTransport.
+func (i *Instrumentation) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{inst: i, base: base}
+}
+
+type transport struct {
+	inst *Instrumentation
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.inst.roundTrip(req, t.base.RoundTrip)
+}
+
+func (i *Instrumentation) roundTrip(req *http.Request, next yadisk.RoundTripFunc) (*http.Response, error) {
+	endpoint := yadisk.RequestEndpoint(req)
+	// Signed upload and download hrefs carry credentials in the path and
+	// query, so only their host is recorded.
+	signed := endpoint == yadisk.EndpointUpload || endpoint == yadisk.EndpointDownload
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("yadisk.endpoint", endpoint),
+		attribute.String("server.address", req.URL.Hostname()),
+	}
+	if !signed {
+		attrs = append(attrs, attribute.String("url.path", req.URL.Path))
+	}
+	ctx, span := i.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	req = req.Clone(ctx)
+	i.propagators.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := next(req)
+	metricAttrs := []attribute.KeyValue{attrs[0], attrs[1]}
+	if err != nil {
+		recorded := err
+		if signed {
+			recorded = redactError(err)
+		}
+		span.RecordError(recorded)
+		span.SetStatus(codes.Error, recorded.Error())
+		metricAttrs = append(metricAttrs, attribute.String("error.type", "transport"))
+	} else {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if id := resp.Header.Get("X-Request-Id"); id != "" {
+			span.SetAttributes(attribute.String("yadisk.request_id", id))
+		}
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, strconv.Itoa(resp.StatusCode))
+		}
+		metricAttrs = append(metricAttrs, attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	i.requestDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(metricAttrs...))
+	return resp, err
+}
+
+// redactError strips the signed href a *url.Error embeds in its text,
+// keeping the scheme and host.
+func redactError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || urlErr.URL == "" {
+		return err
+	}
+	redacted := "REDACTED"
+	if u, perr := url.Parse(urlErr.URL); perr == nil {
+		redacted = (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+	}
+	return errors.New(strings.ReplaceAll(err.Error(), urlErr.URL, redacted))
+}
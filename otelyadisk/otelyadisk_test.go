@@ -0,0 +1,220 @@
+package otelyadisk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	yadisk "github.com/grixate/yandex-disk-go-v2"
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type harness struct {
+	client *yadisk.Client
+	srv    *fakedisk.Server
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+}
+
+func newHarness(t *testing.T, opts ...yadisk.Option) *harness {
+	t.Helper()
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	inst, err := New(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatalf("new instrumentation: %v", err)
+	}
+	opts = append(append(inst.ClientOptions(), yadisk.WithOAuthToken("token"), yadisk.WithBaseURL(srv.URL)), opts...)
+	client, err := yadisk.NewClient(opts...)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return &harness{client: client, srv: srv, spans: spans, reader: reader}
+}
+
+func (h *harness) metrics(t *testing.T) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := h.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	out := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			out[m.Name] = m.Data
+		}
+	}
+	return out
+}
+
+func TestChunkedUploadSpans(t *testing.T) {
+	h := newHarness(t)
+	ctx := context.Background()
+
+	link, err := h.client.Uploads.GetUploadURL(ctx, yadisk.UploadURLRequest{Path: "disk:/big.bin"})
+	if err != nil {
+		t.Fatalf("upload url: %v", err)
+	}
+	data := bytes.Repeat([]byte("x"), 10)
+	if _, err := h.client.Uploads.UploadInChunks(ctx, link, bytes.NewReader(data), yadisk.UploadChunkRequest{PartSize: 4}); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+
+	byID := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range h.spans.Ended() {
+		byID[s.SpanContext().SpanID().String()] = s
+	}
+	var root sdktrace.ReadOnlySpan
+	chunks, attempts := 0, 0
+	for _, s := range h.spans.Ended() {
+		parent := byID[s.Parent().SpanID().String()]
+		switch s.Name() {
+		case "Uploads.UploadInChunks":
+			root = s
+		case "Uploads.UploadChunk":
+			chunks++
+			if parent == nil || parent.Name() != "Uploads.UploadInChunks" {
+				t.Fatalf("chunk span parent = %v", parent)
+			}
+		case "HTTP PUT":
+			attempts++
+			if parent == nil || parent.Name() != "Uploads.UploadChunk" {
+				t.Fatalf("attempt span parent = %v", parent)
+			}
+		}
+	}
+	if root == nil || chunks != 3 || attempts != 3 {
+		t.Fatalf("root=%v chunks=%d attempts=%d", root, chunks, attempts)
+	}
+
+	sum, ok := h.metrics(t)["yadisk.client.transfer.bytes"].(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != int64(len(data)) {
+		t.Fatalf("transfer bytes = %+v", sum)
+	}
+}
+
+func TestCallErrorsAndOperationDuration(t *testing.T) {
+	h := newHarness(t)
+	h.srv.Async = true
+	h.srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := h.client.Resources.GetMeta(ctx, yadisk.ResourceGetRequest{Path: "disk:/missing"}); err == nil {
+		t.Fatal("expected not found")
+	}
+
+	res, err := h.client.Resources.Copy(ctx, yadisk.CopyMoveRequest{From: "disk:/a.txt", Path: "disk:/b.txt"})
+	if err != nil || res.Operation == nil {
+		t.Fatalf("copy: %+v %v", res, err)
+	}
+	if err := h.client.Worker.Start(ctx); err != nil {
+		t.Fatalf("start worker: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := h.client.Close(context.Background()); err != nil {
+			t.Errorf("close: %v", err)
+		}
+	})
+	done := make(chan struct{})
+	if err := h.client.Worker.Watch(*res.Operation, func(e yadisk.OperationEvent) {
+		if e.Done {
+			close(done)
+		}
+	}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("operation never finished")
+	}
+
+	var failed bool
+	for _, s := range h.spans.Ended() {
+		if s.Name() == "Resources.GetMeta" && s.Status().Code == codes.Error {
+			failed = true
+		}
+	}
+	if !failed {
+		t.Fatal("GetMeta span not marked as error")
+	}
+
+	m := h.metrics(t)
+	if hist, ok := m["yadisk.client.operation.duration"].(metricdata.Histogram[float64]); !ok || len(hist.DataPoints) != 1 {
+		t.Fatalf("operation duration = %+v", m["yadisk.client.operation.duration"])
+	}
+	if hist, ok := m["yadisk.client.request.duration"].(metricdata.Histogram[float64]); !ok || len(hist.DataPoints) == 0 {
+		t.Fatalf("request duration = %+v", m["yadisk.client.request.duration"])
+	}
+}
+
+type failingTransport struct {
+	next http.RoundTripper
+	fail func(*http.Request) bool
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.fail(req) {
+		return nil, errors.New("connection reset")
+	}
+	return t.next.RoundTrip(req)
+}
+
+func TestSignedHrefsAreNotRecorded(t *testing.T) {
+	h := newHarness(t, yadisk.WithHTTPClient(&http.Client{Transport: failingTransport{
+		next: http.DefaultTransport,
+		fail: func(r *http.Request) bool { return r.URL.Path == "/download" },
+	}}))
+	h.srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+
+	if _, err := h.client.Uploads.OpenDownload(context.Background(), yadisk.DownloadURLRequest{Path: "disk:/a.txt"}); err == nil {
+		t.Fatal("expected the download to fail")
+	}
+	var found bool
+	for _, s := range h.spans.Ended() {
+		var endpoint string
+		for _, a := range s.Attributes() {
+			if a.Key == "yadisk.endpoint" {
+				endpoint = a.Value.AsString()
+			}
+		}
+		if endpoint != yadisk.EndpointDownload {
+			continue
+		}
+		found = true
+		for _, a := range s.Attributes() {
+			if a.Key == "url.path" {
+				t.Fatalf("signed href path recorded: %s", a.Value.AsString())
+			}
+		}
+		if s.Status().Code != codes.Error || strings.Contains(s.Status().Description, "/download") {
+			t.Fatalf("status = %+v", s.Status())
+		}
+		for _, e := range s.Events() {
+			for _, a := range e.Attributes {
+				if strings.Contains(a.Value.Emit(), "/download") {
+					t.Fatalf("signed href in span event: %s", a.Value.Emit())
+				}
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no span for the signed href")
+	}
+}
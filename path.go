@@ -0,0 +1,258 @@
+package yadisk
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxPathSegment = 255
+
+type PathScheme string
+
+const (
+	SchemeDisk  PathScheme = "disk"
+	SchemeApp   PathScheme = "app"
+	SchemeTrash PathScheme = "trash"
+	// SchemePublic marks a path inside a public resource, which the API
+	// takes without a prefix next to the public key.
+	SchemePublic PathScheme = "public"
+)
+
+// NormalizeUnicode, when set, is applied to every path ParsePath accepts and
+// every element passed to the Path constructors. Set it to norm.NFC.String
+// from golang.org/x/text to fold the decomposed names some file systems
+// produce; the SDK does not depend on x/text itself.
+var NormalizeUnicode func(string) string
+
+// Path is a cleaned Disk path with an explicit scheme. The zero Path is
+// empty; its String is "".
+type Path struct {
+	scheme PathScheme
+	p      string
+}
+
+// ParsePath accepts "disk:/a", "app:/a", "trash:/a", "/a" and "a", the last
+// two being disk paths as in the API. Repeated and trailing slashes and "."
+// segments are removed; ".." segments, control characters, invalid UTF-8
+// and segments longer than 255 bytes are rejected.
+func ParsePath(s string) (Path, error) {
+	if s == "" {
+		return Path{}, errors.New("path is empty")
+	}
+	scheme := SchemeDisk
+	rest := s
+	for _, sc := range []PathScheme{SchemeDisk, SchemeApp, SchemeTrash} {
+		if strings.HasPrefix(s, string(sc)+":") {
+			scheme, rest = sc, strings.TrimPrefix(s, string(sc)+":")
+			break
+		}
+	}
+	for _, seg := range strings.Split(rest, "/") {
+		if seg == ".." {
+			return Path{}, fmt.Errorf("path %q: \"..\" segments are not allowed", s)
+		}
+	}
+	p := newPath(scheme, rest)
+	if err := p.Validate(); err != nil {
+		return Path{}, err
+	}
+	return p, nil
+}
+
+// MustParsePath is like ParsePath but panics on error. It is meant for
+// constants.
+func MustParsePath(s string) Path {
+	p, err := ParsePath(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// DiskPath joins elem into a disk path, so DiskPath("docs", "a.txt") is
+// "disk:/docs/a.txt".
+func DiskPath(elem ...string) Path {
+	return newPath(SchemeDisk, elem...)
+}
+
+// AppPath joins elem into a path in the application folder.
+func AppPath(elem ...string) Path {
+	return newPath(SchemeApp, elem...)
+}
+
+func TrashPath(elem ...string) Path {
+	return newPath(SchemeTrash, elem...)
+}
+
+// PublicPath joins elem into a path inside a public resource.
+func PublicPath(elem ...string) Path {
+	return newPath(SchemePublic, elem...)
+}
+
+func newPath(scheme PathScheme, elem ...string) Path {
+	joined := strings.Join(elem, "/")
+	if NormalizeUnicode != nil {
+		joined = NormalizeUnicode(joined)
+	}
+	return Path{scheme: scheme, p: path.Clean("/" + joined)}
+}
+
+func (p Path) Scheme() PathScheme {
+	return p.scheme
+}
+
+func (p Path) IsZero() bool {
+	return p.scheme == ""
+}
+
+func (p Path) IsRoot() bool {
+	return p.p == "/"
+}
+
+// String returns the form the API takes: "disk:/a", "app:/a", "trash:/a",
+// or "/a" for public paths.
+func (p Path) String() string {
+	switch p.scheme {
+	case "":
+		return ""
+	case SchemePublic:
+		return p.p
+	default:
+		return string(p.scheme) + ":" + p.p
+	}
+}
+
+// Join appends elem. ".." elements are resolved but never climb above the
+// root.
+func (p Path) Join(elem ...string) Path {
+	return newPath(p.scheme, append([]string{p.p}, elem...)...)
+}
+
+// Dir returns the parent folder. The root is its own parent.
+func (p Path) Dir() Path {
+	return Path{scheme: p.scheme, p: path.Dir(p.p)}
+}
+
+// Base returns the last element, or "/" for the root.
+func (p Path) Base() string {
+	return path.Base(p.p)
+}
+
+func (p Path) Ext() string {
+	return path.Ext(p.p)
+}
+
+// Contains reports whether other is p or below it, in the same scheme.
+func (p Path) Contains(other Path) bool {
+	if p.scheme != other.scheme {
+		return false
+	}
+	return p.p == "/" || other.p == p.p || strings.HasPrefix(other.p, p.p+"/")
+}
+
+// Rel returns target relative to p, slash separated, or "." when they are
+// equal. It fails unless p contains target.
+func (p Path) Rel(target Path) (string, error) {
+	if !p.Contains(target) {
+		return "", fmt.Errorf("%s is not below %s", target, p)
+	}
+	if target.p == p.p {
+		return ".", nil
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(target.p, p.p), "/"), nil
+}
+
+// Validate reports control characters, invalid UTF-8 and overlong segments.
+func (p Path) Validate() error {
+	if p.IsZero() {
+		return errors.New("path is empty")
+	}
+	if !utf8.ValidString(p.p) {
+		return fmt.Errorf("path %q is not valid UTF-8", p.p)
+	}
+	for _, seg := range strings.Split(strings.TrimPrefix(p.p, "/"), "/") {
+		if len(seg) > maxPathSegment {
+			return fmt.Errorf("path %q: a name is longer than %d bytes", p.String(), maxPathSegment)
+		}
+		for _, r := range seg {
+			if unicode.IsControl(r) {
+				return fmt.Errorf("path %q: control character %U is not allowed", p.String(), r)
+			}
+		}
+	}
+	return nil
+}
+
+func (p Path) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *Path) UnmarshalText(b []byte) error {
+	if len(b) == 0 {
+		*p = Path{}
+		return nil
+	}
+	parsed, err := ParsePath(string(b))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// Request constructors for Path values. Other fields keep their zero values
+// and can be set on the result.
+
+func NewResourceGetRequest(p Path) ResourceGetRequest {
+	return ResourceGetRequest{Path: p.String()}
+}
+
+func NewResourceUpdateRequest(p Path, props map[string]any) ResourceUpdateRequest {
+	return ResourceUpdateRequest{Path: p.String(), CustomProperties: props}
+}
+
+func NewCreateFolderRequest(p Path) CreateFolderRequest {
+	return CreateFolderRequest{Path: p.String()}
+}
+
+func NewCopyMoveRequest(from, to Path) CopyMoveRequest {
+	return CopyMoveRequest{From: from.String(), Path: to.String()}
+}
+
+func NewDeleteResourceRequest(p Path) DeleteResourceRequest {
+	return DeleteResourceRequest{Path: p.String()}
+}
+
+func NewPublishRequest(p Path) PublishRequest {
+	return PublishRequest{Path: p.String()}
+}
+
+func NewUploadURLRequest(p Path) UploadURLRequest {
+	return UploadURLRequest{Path: p.String()}
+}
+
+func NewDownloadURLRequest(p Path) DownloadURLRequest {
+	return DownloadURLRequest{Path: p.String()}
+}
+
+func NewTrashRestoreRequest(p Path) TrashRestoreRequest {
+	return TrashRestoreRequest{Path: p.String()}
+}
+
+func NewTrashDeleteRequest(p Path) TrashDeleteRequest {
+	return TrashDeleteRequest{Path: p.String()}
+}
+
+// NewPublicResourceRequest addresses p inside the resource published under
+// publicKey. Pass the zero Path for the resource itself.
+func NewPublicResourceRequest(publicKey string, p Path) PublicResourceRequest {
+	return PublicResourceRequest{PublicKey: publicKey, Path: p.String()}
+}
+
+func NewPublicDownloadRequest(publicKey string, p Path) PublicDownloadRequest {
+	return PublicDownloadRequest{PublicKey: publicKey, Path: p.String()}
+}
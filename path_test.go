@@ -0,0 +1,94 @@
+package yadisk
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		want   string
+		scheme PathScheme
+	}{
+		{"disk:/a/b", "disk:/a/b", SchemeDisk},
+		{"/a//b/", "disk:/a/b", SchemeDisk},
+		{"a/./b", "disk:/a/b", SchemeDisk},
+		{"disk:", "disk:/", SchemeDisk},
+		{"app:/cfg.json", "app:/cfg.json", SchemeApp},
+		{"trash:/x_1", "trash:/x_1", SchemeTrash},
+		{"/Фото/снимок 1.jpg", "disk:/Фото/снимок 1.jpg", SchemeDisk},
+	} {
+		p, err := ParsePath(tc.in)
+		if err != nil {
+			t.Fatalf("ParsePath(%q): %v", tc.in, err)
+		}
+		if p.String() != tc.want || p.Scheme() != tc.scheme {
+			t.Fatalf("ParsePath(%q) = %s (%s), want %s", tc.in, p, p.Scheme(), tc.want)
+		}
+	}
+	for _, bad := range []string{"", "/a/../b", "/a\x00b", "/a\nb", "/\xff", "/" + strings.Repeat("n", 256)} {
+		if _, err := ParsePath(bad); err == nil {
+			t.Fatalf("ParsePath(%q) accepted", bad)
+		}
+	}
+}
+
+func TestPathOperations(t *testing.T) {
+	docs := DiskPath("docs")
+	file := docs.Join("sub", "report.pdf")
+	if file.String() != "disk:/docs/sub/report.pdf" || file.Base() != "report.pdf" || file.Ext() != ".pdf" {
+		t.Fatalf("join = %s", file)
+	}
+	if file.Dir().Dir() != docs || !DiskPath().IsRoot() || DiskPath().Dir() != DiskPath() {
+		t.Fatalf("dir of %s = %s", file, file.Dir())
+	}
+	if rel, err := docs.Rel(file); err != nil || rel != "sub/report.pdf" {
+		t.Fatalf("rel = %q, %v", rel, err)
+	}
+	if _, err := docs.Rel(DiskPath("docsx", "a")); err == nil {
+		t.Fatal("rel accepted a sibling with a shared prefix")
+	}
+	if _, err := docs.Rel(AppPath("docs", "a")); err == nil {
+		t.Fatal("rel accepted another scheme")
+	}
+	if docs.Join("..", "..", "etc").String() != "disk:/etc" {
+		t.Fatalf("join climbed above root: %s", docs.Join("..", "..", "etc"))
+	}
+	if PublicPath("a", "b").String() != "/a/b" || (Path{}).String() != "" {
+		t.Fatal("unexpected public or zero path form")
+	}
+
+	req := NewCopyMoveRequest(file, AppPath("backup", "report.pdf"))
+	if req.From != "disk:/docs/sub/report.pdf" || req.Path != "app:/backup/report.pdf" {
+		t.Fatalf("copy request = %+v", req)
+	}
+	if r := NewPublicDownloadRequest("key", Path{}); r.Path != "" || r.PublicKey != "key" {
+		t.Fatalf("public request = %+v", r)
+	}
+}
+
+func TestPathUnicodeAndText(t *testing.T) {
+	defer func() { NormalizeUnicode = nil }()
+	// Stand in for NFC: fold "e" + combining acute into "é".
+	NormalizeUnicode = func(s string) string { return strings.ReplaceAll(s, "e\u0301", "\u00e9") }
+	p, err := ParsePath("/cafe\u0301.txt")
+	if err != nil || p != DiskPath("caf\u00e9.txt") {
+		t.Fatalf("normalized = %s, %v", p, err)
+	}
+
+	var cfg struct {
+		Root Path `json:"root"`
+	}
+	if err := json.Unmarshal([]byte(`{"root":"app:/data/"}`), &cfg); err != nil || cfg.Root != AppPath("data") {
+		t.Fatalf("unmarshal = %s, %v", cfg.Root, err)
+	}
+	out, _ := json.Marshal(cfg)
+	if string(out) != `{"root":"app:/data"}` {
+		t.Fatalf("marshal = %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"root":"/a/../b"}`), &cfg); err == nil {
+		t.Fatal("invalid path unmarshaled")
+	}
+}
@@ -0,0 +1,54 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrPreconditionFailed is returned when an IfMatch precondition does not
+// hold. API errors with status 412 match it too.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// Precondition makes a mutating call conditional on the current state of
+// the resource it replaces or changes. Zero fields are not checked.
+//
+// Delete passes MD5 to the API, which checks it atomically. Every other
+// check is made by the client with a metadata request just before the call,
+// which narrows the window for lost updates but cannot close it.
+type Precondition struct {
+	MD5      string
+	Revision int64
+}
+
+func (p *Precondition) empty() bool {
+	return p == nil || (p.MD5 == "" && p.Revision == 0)
+}
+
+// checkPrecondition fetches path, bypassing the metadata cache, and fails
+// with ErrPreconditionFailed unless it matches pre. A missing resource never
+// matches.
+func (c *Client) checkPrecondition(ctx context.Context, path string, pre *Precondition) error {
+	if pre.empty() {
+		return nil
+	}
+	q := url.Values{}
+	addString(q, "path", path)
+	addCSV(q, "fields", []string{"path", "md5", "revision"})
+	var r Resource
+	if _, err := c.doJSON(ctx, http.MethodGet, "/disk/resources", q, nil, &r, http.StatusOK); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("%w: %s does not exist", ErrPreconditionFailed, path)
+		}
+		return err
+	}
+	if pre.MD5 != "" && r.MD5 != pre.MD5 {
+		return fmt.Errorf("%w: %s has md5 %s, want %s", ErrPreconditionFailed, path, r.MD5, pre.MD5)
+	}
+	if pre.Revision != 0 && r.Revision != pre.Revision {
+		return fmt.Errorf("%w: %s has revision %d, want %d", ErrPreconditionFailed, path, r.Revision, pre.Revision)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPreconditions(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile("disk:/a.txt", []byte("v1"), time.Now())
+	srv.PutFile("disk:/b.txt", []byte("b"), time.Now())
+
+	meta, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"})
+	if err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+	read := &Precondition{MD5: meta.MD5, Revision: meta.Revision}
+	stale := &Precondition{MD5: "0123456789abcdef0123456789abcdef"}
+	overwrite := true
+
+	if _, err := client.Resources.UpdateMeta(ctx, ResourceUpdateRequest{Path: "disk:/a.txt", CustomProperties: map[string]any{"k": "v"}, IfMatch: stale}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("stale update = %v", err)
+	}
+	if _, err := client.Resources.Copy(ctx, CopyMoveRequest{From: "disk:/b.txt", Path: "disk:/a.txt", Overwrite: &overwrite, IfMatch: stale}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("stale copy = %v", err)
+	}
+	if _, err := client.Resources.Move(ctx, CopyMoveRequest{From: "disk:/b.txt", Path: "disk:/missing.txt", IfMatch: read}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("move onto missing file = %v", err)
+	}
+	if _, err := client.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: "disk:/a.txt", Overwrite: &overwrite, IfMatch: stale}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("stale upload = %v", err)
+	}
+	if _, err := client.Resources.Delete(ctx, DeleteResourceRequest{Path: "disk:/a.txt", IfMatch: stale}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("stale delete = %v", err)
+	}
+	if got, _ := srv.File("disk:/a.txt"); string(got) != "v1" {
+		t.Fatalf("file changed to %q", got)
+	}
+
+	link, err := client.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: "disk:/a.txt", Overwrite: &overwrite, IfMatch: read})
+	if err != nil {
+		t.Fatalf("upload link: %v", err)
+	}
+	if _, err := client.Uploads.UploadByLink(ctx, link, bytes.NewReader([]byte("v2"))); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	// The upload changed the file, so the revision read earlier is stale.
+	if _, err := client.Resources.UpdateMeta(ctx, ResourceUpdateRequest{Path: "disk:/a.txt", CustomProperties: map[string]any{"k": "v"}, IfMatch: &Precondition{Revision: meta.Revision}}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("update after upload = %v", err)
+	}
+}
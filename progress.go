@@ -0,0 +1,234 @@
+package yadisk
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultProgressInterval = 500 * time.Millisecond
+
+type TransferDirection string
+
+const (
+	TransferUpload   TransferDirection = "upload"
+	TransferDownload TransferDirection = "download"
+)
+
+// TransferProgress describes a data transfer. Every transfer reports once with
+// BytesDone == 0 when it starts and once with Done set when it ends.
+type TransferProgress struct {
+	Direction TransferDirection
+	BytesDone int64
+	// Total is -1 when the size is unknown.
+	Total int64
+	// Rate is the average throughput since the start in bytes per second.
+	Rate float64
+	// ETA is zero when Total is unknown.
+	ETA time.Duration
+	// Chunk is the 1-based index of the last completed UploadInChunks part.
+	Chunk int
+	Done  bool
+	Err   error
+}
+
+type ProgressFunc func(TransferProgress)
+
+type TransferOption func(*transferOptions)
+
+type transferOptions struct {
+	progress ProgressFunc
+	interval time.Duration
+	size     int64
+	limiters []*BandwidthLimiter
+}
+
+func WithProgress(fn ProgressFunc) TransferOption {
+	return func(o *transferOptions) {
+		o.progress = fn
+	}
+}
+
+// WithProgressInterval sets the minimum time between progress reports; zero
+// reports after every read. Start, chunk completion and end are always
+// reported.
+func WithProgressInterval(d time.Duration) TransferOption {
+	return func(o *transferOptions) {
+		o.interval = d
+	}
+}
+
+// WithTransferSize declares the body size of an UploadByLink whose reader
+// does not expose it.
+func WithTransferSize(n int64) TransferOption {
+	return func(o *transferOptions) {
+		o.size = n
+	}
+}
+
+func newTransferOptions(opts []TransferOption) transferOptions {
+	o := transferOptions{interval: defaultProgressInterval, size: -1}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+type progressTracker struct {
+	dir      TransferDirection
+	total    int64
+	interval time.Duration
+	fns      []ProgressFunc
+
+	mu       sync.Mutex
+	done     int64
+	chunk    int
+	start    time.Time
+	last     time.Time
+	finished bool
+}
+
+// newProgressTracker returns nil when nobody listens, so callers can skip
+// wrapping bodies entirely.
+func (c *Client) newProgressTracker(dir TransferDirection, total int64, o transferOptions) *progressTracker {
+	var fns []ProgressFunc
+	if c.hooks.OnTransferProgress != nil {
+		fns = append(fns, c.hooks.OnTransferProgress)
+	}
+	if o.progress != nil {
+		fns = append(fns, o.progress)
+	}
+	if c.log != nil {
+		fns = append(fns, c.log.transfer)
+	}
+	if len(fns) == 0 {
+		return nil
+	}
+	now := time.Now()
+	t := &progressTracker{dir: dir, total: total, interval: o.interval, fns: fns, start: now, last: now}
+	t.emit(t.snapshot(now))
+	return t
+}
+
+func (t *progressTracker) add(n int) {
+	if t == nil || n <= 0 {
+		return
+	}
+	t.mu.Lock()
+	t.done += int64(n)
+	now := time.Now()
+	if now.Sub(t.last) < t.interval || t.finished {
+		t.mu.Unlock()
+		return
+	}
+	t.last = now
+	p := t.snapshot(now)
+	t.mu.Unlock()
+	t.emit(p)
+}
+
+func (t *progressTracker) completeChunk() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.chunk++
+	now := time.Now()
+	t.last = now
+	p := t.snapshot(now)
+	t.mu.Unlock()
+	t.emit(p)
+}
+
+func (t *progressTracker) finish(err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if t.finished {
+		t.mu.Unlock()
+		return
+	}
+	t.finished = true
+	p := t.snapshot(time.Now())
+	p.Done = true
+	p.Err = err
+	t.mu.Unlock()
+	t.emit(p)
+}
+
+func (t *progressTracker) snapshot(now time.Time) TransferProgress {
+	p := TransferProgress{Direction: t.dir, BytesDone: t.done, Total: t.total, Chunk: t.chunk}
+	if elapsed := now.Sub(t.start).Seconds(); elapsed > 0 {
+		p.Rate = float64(t.done) / elapsed
+	}
+	if t.total > 0 && p.Rate > 0 && t.done < t.total {
+		p.ETA = time.Duration(float64(t.total-t.done) / p.Rate * float64(time.Second))
+	}
+	return p
+}
+
+func (t *progressTracker) emit(p TransferProgress) {
+	for _, fn := range t.fns {
+		fn(p)
+	}
+}
+
+type progressReader struct {
+	r       io.Reader
+	tracker *progressTracker
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.tracker.add(n)
+	return n, err
+}
+
+// progressBody reports download progress and finishes the transfer on EOF,
+// read error or Close, whichever comes first.
+type progressBody struct {
+	io.ReadCloser
+	tracker *progressTracker
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.tracker.add(n)
+	if err == io.EOF {
+		b.tracker.finish(nil)
+	} else if err != nil {
+		b.tracker.finish(err)
+	}
+	return n, err
+}
+
+func (b *progressBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.tracker.finish(err)
+	return err
+}
+
+// readerSize returns the number of bytes left in r when it can be determined
+// without consuming it, or -1.
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		pos, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - pos
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,112 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUploadByLinkReportsProgress(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != 1024 {
+			t.Errorf("content length = %d", r.ContentLength)
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+	})
+	link := &ResourceUploadLink{Link: Link{Href: client.transport.baseURL.String() + "/upload", Method: http.MethodPut}}
+
+	var reports []TransferProgress
+	_, err := client.Uploads.UploadByLink(context.Background(), link, io.MultiReader(bytes.NewReader(make([]byte, 1024))),
+		WithProgress(func(p TransferProgress) { reports = append(reports, p) }),
+		WithProgressInterval(0),
+		WithTransferSize(1024),
+	)
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if len(reports) < 3 {
+		t.Fatalf("reports = %+v", reports)
+	}
+	first, last := reports[0], reports[len(reports)-1]
+	if first.BytesDone != 0 || first.Done || first.Total != 1024 || first.Direction != TransferUpload {
+		t.Fatalf("first = %+v", first)
+	}
+	if !last.Done || last.BytesDone != 1024 || last.Err != nil {
+		t.Fatalf("last = %+v", last)
+	}
+}
+
+func TestUploadInChunksReportsEveryChunk(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	link := &ResourceUploadLink{Link: Link{Href: client.transport.baseURL.String() + "/upload", Method: http.MethodPut}}
+
+	var chunks []int
+	_, err := client.Uploads.UploadInChunks(context.Background(), link, strings.NewReader("hello world"), UploadChunkRequest{PartSize: 4},
+		WithProgress(func(p TransferProgress) {
+			if p.Chunk > 0 && !p.Done {
+				chunks = append(chunks, p.Chunk)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if len(chunks) != 3 || chunks[2] != 3 {
+		t.Fatalf("chunks = %v", chunks)
+	}
+}
+
+func TestOpenDownloadProgressHook(t *testing.T) {
+	var mu sync.Mutex
+	var reports []TransferProgress
+	var baseURL string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/disk/resources/download" {
+			w.Header().Set("Content-Type", "application/json")
+			mustFprint(t, w, `{"href":"`+baseURL+`/file","method":"GET"}`)
+			return
+		}
+		mustFprint(t, w, "0123456789")
+	})
+	baseURL = client.transport.baseURL.String()
+	client.hooks.OnTransferProgress = func(p TransferProgress) {
+		mu.Lock()
+		reports = append(reports, p)
+		mu.Unlock()
+	}
+
+	body, err := client.Uploads.OpenDownload(context.Background(), DownloadURLRequest{Path: "disk:/f"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := io.ReadAll(body); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if err := body.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	last := reports[len(reports)-1]
+	if !last.Done || last.BytesDone != 10 || last.Total != 10 || last.Direction != TransferDownload {
+		t.Fatalf("last = %+v", last)
+	}
+	done := 0
+	for _, p := range reports {
+		if p.Done {
+			done++
+		}
+	}
+	if done != 1 {
+		t.Fatalf("done reported %d times", done)
+	}
+}
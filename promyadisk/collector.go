@@ -0,0 +1,188 @@
+// Package promyadisk exports yadisk.Client health as Prometheus metrics.
+//
+//	collector := promyadisk.NewCollector()
+//	prometheus.MustRegister(collector)
+//	client, err := yadisk.NewClient(yadisk.WithOAuthToken(token), yadisk.WithHooks(collector.Hooks()))
+//	collector.ObserveWorker(client.Worker)
+package promyadisk
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	yadisk "github.com/grixate/yandex-disk-go-v2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "yadisk"
+
+type config struct {
+	constLabels prometheus.Labels
+	buckets     []float64
+}
+
+type Option func(*config)
+
+// WithConstLabels attaches labels to every metric, for example to tell
+// several clients in one process apart.
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(c *config) {
+		c.constLabels = labels
+	}
+}
+
+// WithBuckets sets the histogram buckets, in seconds, for request and
+// operation durations. Defaults to prometheus.DefBuckets.
+func WithBuckets(buckets []float64) Option {
+	return func(c *config) {
+		c.buckets = buckets
+	}
+}
+
+// Collector is a prometheus.Collector fed by the hooks returned from Hooks.
+type Collector struct {
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	retries           *prometheus.CounterVec
+	throttled         *prometheus.CounterVec
+	transfersInFlight *prometheus.GaugeVec
+	transferredBytes  *prometheus.CounterVec
+	operations        *prometheus.CounterVec
+	operationDuration *prometheus.HistogramVec
+	queueDepth        *prometheus.Desc
+
+	mu     sync.Mutex
+	worker *yadisk.OperationWorker
+}
+
+func NewCollector(opts ...Option) *Collector {
+	cfg := config{buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	labels := cfg.constLabels
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "requests_total", ConstLabels: labels,
+			Help: "HTTP responses received, by endpoint and status code.",
+		}, []string{"method", "endpoint", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "request_duration_seconds", ConstLabels: labels, Buckets: cfg.buckets,
+			Help: "Duration of HTTP attempts, by endpoint.",
+		}, []string{"method", "endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "retries_total", ConstLabels: labels,
+			Help: "Requests retried, by endpoint and reason.",
+		}, []string{"method", "endpoint", "reason"}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "throttled_total", ConstLabels: labels,
+			Help: "Responses with status 429 Too Many Requests, by endpoint.",
+		}, []string{"endpoint"}),
+		transfersInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "transfers_in_flight", ConstLabels: labels,
+			Help: "Uploads and downloads currently in progress.",
+		}, []string{"direction"}),
+		transferredBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "transfer_bytes_total", ConstLabels: labels,
+			Help: "Bytes uploaded and downloaded by finished transfers.",
+		}, []string{"direction"}),
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "operations_total", ConstLabels: labels,
+			Help: "Asynchronous operations that reached a terminal status.",
+		}, []string{"status"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "operation_duration_seconds", ConstLabels: labels, Buckets: cfg.buckets,
+			Help: "Time from watching an asynchronous operation until it finished.",
+		}, []string{"status"}),
+		queueDepth: prometheus.NewDesc(prometheus.BuildFQName(namespace, "worker", "queue_depth"),
+			"Operations currently watched by the observed OperationWorker.", nil, labels),
+	}
+}
+
+// ObserveWorker reports w's queue depth on every scrape.
+func (c *Collector) ObserveWorker(w *yadisk.OperationWorker) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.worker = w
+}
+
+func (c *Collector) Hooks() yadisk.Hooks {
+	return yadisk.Hooks{
+		OnResponse:         c.observeResponse,
+		OnRetry:            c.observeRetry,
+		OnTransferProgress: c.observeTransfer,
+		OnOperationEvent:   c.observeOperation,
+	}
+}
+
+func (c *Collector) observeResponse(resp *http.Response, d time.Duration) {
+	endpoint := yadisk.RequestEndpoint(resp.Request)
+	if endpoint == "" {
+		endpoint = "unknown"
+	}
+	method := ""
+	if resp.Request != nil {
+		method = resp.Request.Method
+	}
+	c.requests.WithLabelValues(method, endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+	c.requestDuration.WithLabelValues(method, endpoint).Observe(d.Seconds())
+	if resp.StatusCode == http.StatusTooManyRequests {
+		c.throttled.WithLabelValues(endpoint).Inc()
+	}
+}
+
+func (c *Collector) observeRetry(e yadisk.RetryEvent) {
+	reason := "error"
+	if e.StatusCode != 0 {
+		reason = strconv.Itoa(e.StatusCode)
+	}
+	c.retries.WithLabelValues(e.Method, e.Endpoint, reason).Inc()
+}
+
+func (c *Collector) observeTransfer(p yadisk.TransferProgress) {
+	direction := string(p.Direction)
+	switch {
+	case p.Done:
+		c.transfersInFlight.WithLabelValues(direction).Dec()
+		c.transferredBytes.WithLabelValues(direction).Add(float64(p.BytesDone))
+	case p.BytesDone == 0:
+		c.transfersInFlight.WithLabelValues(direction).Inc()
+	}
+}
+
+func (c *Collector) observeOperation(e yadisk.OperationEvent) {
+	if !e.Done {
+		return
+	}
+	c.operations.WithLabelValues(e.Status).Inc()
+	c.operationDuration.WithLabelValues(e.Status).Observe(e.Elapsed.Seconds())
+}
+
+func (c *Collector) vectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.requests, c.requestDuration, c.retries, c.throttled,
+		c.transfersInFlight, c.transferredBytes, c.operations, c.operationDuration,
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, v := range c.vectors() {
+		v.Describe(ch)
+	}
+	ch <- c.queueDepth
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, v := range c.vectors() {
+		v.Collect(ch)
+	}
+	c.mu.Lock()
+	w := c.worker
+	c.mu.Unlock()
+	if w != nil {
+		ch <- prometheus.MustNewConstMetric(c.queueDepth, prometheus.GaugeValue, float64(w.Len()))
+	}
+}
@@ -0,0 +1,110 @@
+package promyadisk
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	yadisk "github.com/grixate/yandex-disk-go-v2"
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollectorRequestsAndRetries(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"TooManyRequestsError"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	t.Cleanup(ts.Close)
+
+	collector := NewCollector()
+	client, err := yadisk.NewClient(
+		yadisk.WithOAuthToken("token"),
+		yadisk.WithBaseURL(ts.URL),
+		yadisk.WithHooks(collector.Hooks()),
+		yadisk.WithRetryPolicy(yadisk.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	if _, err := client.Operations.GetStatus(context.Background(), yadisk.OperationStatusRequest{OperationID: "abc"}); err != nil {
+		t.Fatalf("get status: %v", err)
+	}
+
+	const endpoint = "/disk/operations/{id}"
+	if got := testutil.ToFloat64(collector.requests.WithLabelValues(http.MethodGet, endpoint, "429")); got != 1 {
+		t.Fatalf("429 responses = %v", got)
+	}
+	if got := testutil.ToFloat64(collector.requests.WithLabelValues(http.MethodGet, endpoint, "200")); got != 1 {
+		t.Fatalf("200 responses = %v", got)
+	}
+	if got := testutil.ToFloat64(collector.retries.WithLabelValues(http.MethodGet, endpoint, "429")); got != 1 {
+		t.Fatalf("retries = %v", got)
+	}
+	if got := testutil.ToFloat64(collector.throttled.WithLabelValues(endpoint)); got != 1 {
+		t.Fatalf("throttled = %v", got)
+	}
+}
+
+func TestCollectorTransfersAndWorker(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	srv.Async = true
+	srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+
+	collector := NewCollector(WithConstLabels(prometheus.Labels{"client": "test"}))
+	client, err := yadisk.NewClient(yadisk.WithOAuthToken("token"), yadisk.WithBaseURL(srv.URL), yadisk.WithHooks(collector.Hooks()))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	collector.ObserveWorker(client.Worker)
+	ctx := context.Background()
+
+	link, err := client.Uploads.GetUploadURL(ctx, yadisk.UploadURLRequest{Path: "disk:/b.txt"})
+	if err != nil {
+		t.Fatalf("upload url: %v", err)
+	}
+	if _, err := client.Uploads.UploadByLink(ctx, link, bytes.NewReader([]byte("hello"))); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if got := testutil.ToFloat64(collector.transferredBytes.WithLabelValues("upload")); got != 5 {
+		t.Fatalf("uploaded bytes = %v", got)
+	}
+	if got := testutil.ToFloat64(collector.transfersInFlight.WithLabelValues("upload")); got != 0 {
+		t.Fatalf("in flight = %v", got)
+	}
+	if got := testutil.ToFloat64(collector.requests.WithLabelValues(http.MethodPut, yadisk.EndpointUpload, "201")); got != 1 {
+		t.Fatalf("upload responses = %v", got)
+	}
+
+	res, err := client.Resources.Copy(ctx, yadisk.CopyMoveRequest{From: "disk:/a.txt", Path: "disk:/c.txt"})
+	if err != nil || res.Operation == nil {
+		t.Fatalf("copy: %+v %v", res, err)
+	}
+	if err := client.Worker.Watch(*res.Operation, func(yadisk.OperationEvent) {}); err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(collector)
+	expected := `
+# HELP yadisk_worker_queue_depth Operations currently watched by the observed OperationWorker.
+# TYPE yadisk_worker_queue_depth gauge
+yadisk_worker_queue_depth{client="test"} 1
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "yadisk_worker_queue_depth"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,121 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxCustomPropertiesSize is the API limit on the JSON-encoded custom
+	// properties of one resource, in bytes.
+	MaxCustomPropertiesSize = 1024
+	// MaxCustomPropertiesKeys caps the number of keys on one resource.
+	MaxCustomPropertiesKeys = 100
+)
+
+var ErrPropertiesTooLarge = errors.New("custom properties exceed the API limits")
+
+// GetProperties decodes the custom properties of r into a T, typically a
+// struct with json tags.
+func GetProperties[T any](r *Resource) (T, error) {
+	var out T
+	if r == nil || len(r.CustomProperties) == 0 {
+		return out, nil
+	}
+	data, err := json.Marshal(r.CustomProperties)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("decode custom properties: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateProperties merges v, a struct with json tags or a map, into the
+// custom properties of path. Keys v encodes as null are deleted and keys it
+// does not encode are left as they are. The merged properties are checked
+// against the API limits before anything is sent, which costs one extra
+// request for the current properties.
+func (s *ResourcesService) UpdateProperties(ctx context.Context, path string, v any) (_ *Resource, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.UpdateProperties")
+	defer func() { end(err) }()
+
+	patch, err := propertiesPatch(v)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.GetMeta(ctx, ResourceGetRequest{Path: path, Fields: []string{"custom_properties"}})
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateProperties(mergeProperties(current.CustomProperties, patch)); err != nil {
+		return nil, err
+	}
+	return s.UpdateMeta(ctx, ResourceUpdateRequest{Path: path, CustomProperties: patch})
+}
+
+// DeleteProperties removes keys from the custom properties of path.
+func (s *ResourcesService) DeleteProperties(ctx context.Context, path string, keys ...string) (*Resource, error) {
+	patch := make(map[string]any, len(keys))
+	for _, k := range keys {
+		patch[k] = nil
+	}
+	return s.UpdateMeta(ctx, ResourceUpdateRequest{Path: path, CustomProperties: patch})
+}
+
+// ValidateProperties checks props against the API limits.
+func ValidateProperties(props map[string]any) error {
+	for k := range props {
+		if k == "" {
+			return errors.New("custom property names must not be empty")
+		}
+	}
+	if len(props) > MaxCustomPropertiesKeys {
+		return fmt.Errorf("%w: %d keys, limit is %d", ErrPropertiesTooLarge, len(props), MaxCustomPropertiesKeys)
+	}
+	data, err := json.Marshal(props)
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxCustomPropertiesSize {
+		return fmt.Errorf("%w: %d keys encode to %d bytes, limit is %d", ErrPropertiesTooLarge, len(props), len(data), MaxCustomPropertiesSize)
+	}
+	return nil
+}
+
+func propertiesPatch(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return nil, fmt.Errorf("custom properties must encode to a JSON object, got %T", v)
+	}
+	var patch map[string]any
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	if len(patch) == 0 {
+		return nil, errors.New("no custom properties to update")
+	}
+	return patch, nil
+}
+
+func mergeProperties(current, patch map[string]any) map[string]any {
+	out := make(map[string]any, len(current)+len(patch))
+	for k, v := range current {
+		out[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
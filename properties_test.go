@@ -0,0 +1,83 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testProps struct {
+	Owner  string   `json:"owner,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+	Score  *int     `json:"score"`
+}
+
+func TestUpdatePropertiesMergesAndDeletes(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+
+	score := 7
+	if _, err := client.Resources.UpdateProperties(ctx, "disk:/a.txt", testProps{Owner: "ann", Score: &score}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, err := client.Resources.UpdateProperties(ctx, "disk:/a.txt", map[string]any{"extra": "x"}); err != nil {
+		t.Fatalf("update map: %v", err)
+	}
+	r, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, err := GetProperties[testProps](r)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Owner != "ann" || got.Score == nil || *got.Score != 7 || r.CustomProperties["extra"] != "x" {
+		t.Fatalf("properties = %+v", r.CustomProperties)
+	}
+
+	// A nil Score encodes as null and deletes the key; omitted fields stay.
+	r, err = client.Resources.UpdateProperties(ctx, "disk:/a.txt", testProps{Labels: []string{"l"}})
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if _, ok := r.CustomProperties["score"]; ok || r.CustomProperties["owner"] != "ann" {
+		t.Fatalf("properties = %+v", r.CustomProperties)
+	}
+	r, err = client.Resources.DeleteProperties(ctx, "disk:/a.txt", "owner", "extra")
+	if err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(r.CustomProperties) != 1 {
+		t.Fatalf("properties = %+v", r.CustomProperties)
+	}
+}
+
+func TestUpdatePropertiesValidates(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile("disk:/a.txt", []byte("a"), time.Now())
+
+	if _, err := client.Resources.UpdateProperties(ctx, "disk:/a.txt", []string{"x"}); err == nil {
+		t.Fatal("non-object value accepted")
+	}
+	if _, err := client.Resources.UpdateProperties(ctx, "disk:/a.txt", map[string]any{"": 1}); err == nil {
+		t.Fatal("empty key accepted")
+	}
+	big := map[string]any{"blob": strings.Repeat("x", MaxCustomPropertiesSize)}
+	if _, err := client.Resources.UpdateProperties(ctx, "disk:/a.txt", big); !errors.Is(err, ErrPropertiesTooLarge) {
+		t.Fatalf("oversized update = %v", err)
+	}
+	many := map[string]any{}
+	for i := 0; i <= MaxCustomPropertiesKeys; i++ {
+		many[string(rune('a'+i%26))+strings.Repeat("k", i/26)] = 1
+	}
+	if err := ValidateProperties(many); !errors.Is(err, ErrPropertiesTooLarge) {
+		t.Fatalf("too many keys = %v", err)
+	}
+	if r, _ := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/a.txt"}); len(r.CustomProperties) != 0 {
+		t.Fatalf("rejected update reached the server: %+v", r.CustomProperties)
+	}
+}
@@ -0,0 +1,212 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	defaultTreeConcurrency = 4
+	defaultTreeAttempts    = 3
+	publicTreePageSize     = 100
+)
+
+type DownloadTreeConfig struct {
+	// Path selects a folder inside the public resource. Defaults to its root.
+	Path string
+	// Concurrency bounds parallel file downloads. Defaults to 4.
+	Concurrency int
+	// Attempts is how many times each file is tried before it is reported
+	// as failed, waiting with the client's retry backoff in between.
+	// Defaults to 3.
+	Attempts int
+	// SkipExisting leaves local files alone when their size and MD5 match.
+	SkipExisting bool
+	// Transfer options apply to every file, so WithProgress reports each
+	// file separately and WithLimiter caps the whole tree.
+	Transfer []TransferOption
+	// OnFile is called from the download goroutines as each file finishes.
+	OnFile func(DownloadTreeItem)
+}
+
+type DownloadTreeItem struct {
+	// Path is the file's path inside the public resource.
+	Path     string
+	Local    string
+	Size     int64
+	Attempts int
+	Skipped  bool
+	Err      error
+}
+
+type DownloadTreeReport struct {
+	Items []DownloadTreeItem
+}
+
+func (r *DownloadTreeReport) Failed() []DownloadTreeItem {
+	var out []DownloadTreeItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// DownloadTree copies every file of a public folder into localDir, keeping
+// the folder layout. A public file is saved as localDir/<name>. The report
+// covers every file listed, sorted by path; the returned error joins the
+// per-file failures, or is the listing error if the tree could not be read.
+func (s *PublicService) DownloadTree(ctx context.Context, publicKey, localDir string, cfg DownloadTreeConfig) (_ *DownloadTreeReport, err error) {
+	ctx, end := s.client.startCall(ctx, "Public.DownloadTree")
+	defer func() { end(err) }()
+
+	if publicKey == "" {
+		return nil, errors.New("public_key is required")
+	}
+	if localDir == "" {
+		return nil, errors.New("local dir is required")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultTreeConcurrency
+	}
+	if cfg.Attempts <= 0 {
+		cfg.Attempts = defaultTreeAttempts
+	}
+	root := path.Clean("/" + cfg.Path)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		report = &DownloadTreeReport{}
+		files  = make(chan PublicResource)
+		wg     sync.WaitGroup
+	)
+	for w := 0; w < cfg.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range files {
+				item := s.downloadTreeFile(runCtx, publicKey, localDir, root, f, cfg)
+				if cfg.OnFile != nil {
+					cfg.OnFile(item)
+				}
+				mu.Lock()
+				report.Items = append(report.Items, item)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	listErr := s.walkPublic(runCtx, publicKey, root, func(r PublicResource) error {
+		select {
+		case files <- r:
+			return nil
+		case <-runCtx.Done():
+			return runCtx.Err()
+		}
+	})
+	close(files)
+	wg.Wait()
+	sort.Slice(report.Items, func(i, j int) bool { return report.Items[i].Path < report.Items[j].Path })
+
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	if listErr != nil {
+		return report, listErr
+	}
+	var errs []error
+	for _, item := range report.Items {
+		if item.Err != nil {
+			errs = append(errs, fmt.Errorf("download %s: %w", item.Path, item.Err))
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+// walkPublic calls fn for every file below dir inside a public resource.
+func (s *PublicService) walkPublic(ctx context.Context, publicKey, dir string, fn func(PublicResource) error) error {
+	limit := publicTreePageSize
+	offset := 0
+	for {
+		page := offset
+		res, err := s.GetMeta(ctx, PublicResourceRequest{PublicKey: publicKey, Path: dir, Limit: &limit, Offset: &page})
+		if err != nil {
+			return err
+		}
+		if res.Type != "dir" {
+			return fn(*res)
+		}
+		for _, item := range res.Embedded.Items {
+			if item.Type == "dir" {
+				if err := s.walkPublic(ctx, publicKey, item.Path, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		offset += len(res.Embedded.Items)
+		if len(res.Embedded.Items) == 0 || offset >= res.Embedded.Total {
+			return nil
+		}
+	}
+}
+
+func (s *PublicService) downloadTreeFile(ctx context.Context, publicKey, localDir, root string, f PublicResource, cfg DownloadTreeConfig) DownloadTreeItem {
+	item := DownloadTreeItem{Path: f.Path, Size: f.Size}
+	rel := strings.TrimPrefix(strings.TrimPrefix(path.Clean("/"+f.Path), root), "/")
+	if rel == "" {
+		rel = f.Name
+	}
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		item.Err = fmt.Errorf("unsafe public path %q", f.Path)
+		return item
+	}
+	item.Local = filepath.Join(localDir, filepath.FromSlash(rel))
+
+	if cfg.SkipExisting && f.MD5 != "" {
+		if info, err := os.Stat(item.Local); err == nil && info.Size() == f.Size {
+			if sum, err := fileMD5(item.Local); err == nil && sum == f.MD5 {
+				item.Skipped = true
+				return item
+			}
+		}
+	}
+
+	for item.Attempts = 1; ; item.Attempts++ {
+		item.Err = s.downloadPublicFile(ctx, publicKey, f, item.Local, cfg.Transfer)
+		if item.Err == nil || item.Attempts >= cfg.Attempts || ctx.Err() != nil {
+			return item
+		}
+		if err := sleepWithContext(ctx, s.client.backoff(item.Attempts)); err != nil {
+			return item
+		}
+	}
+}
+
+func (s *PublicService) downloadPublicFile(ctx context.Context, publicKey string, f PublicResource, dest string, opts []TransferOption) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	body, err := s.OpenDownload(ctx, PublicDownloadRequest{PublicKey: publicKey, Path: f.Path}, opts...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	_, err = writeDownload(dest, body, f.Path, f.MD5, f.Modified)
+	return err
+}
@@ -0,0 +1,150 @@
+package yadisk
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func TestPublishWithSettings(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile("disk:/share/a.txt", []byte("a"), time.Now())
+
+	readOnly := true
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := client.Resources.Publish(ctx, PublishRequest{
+		Path: "disk:/share",
+		PublicSettings: &PublicSettings{
+			ReadOnly:       &readOnly,
+			Password:       "secret",
+			AvailableUntil: until.Unix(),
+			Accesses:       []PublicAccess{{Type: "macro", Macros: []string{"all"}, Rights: []string{"read"}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	got, err := client.Resources.GetPublicSettings(ctx, "disk:/share")
+	if err != nil {
+		t.Fatalf("get settings: %v", err)
+	}
+	if got.ReadOnly == nil || !*got.ReadOnly || got.Password != "secret" || !got.AvailableUntilTime().Equal(until) {
+		t.Fatalf("settings = %+v", got)
+	}
+	if len(got.Accesses) != 1 || got.Accesses[0].Macros[0] != "all" {
+		t.Fatalf("accesses = %+v", got.Accesses)
+	}
+
+	if err := client.Resources.UpdatePublicSettings(ctx, "disk:/share", PublicSettings{Password: "changed"}); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	got, err = client.Resources.GetPublicSettings(ctx, "disk:/share")
+	if err != nil {
+		t.Fatalf("get settings: %v", err)
+	}
+	if got.Password != "changed" || got.ReadOnly == nil || !*got.ReadOnly {
+		t.Fatalf("settings after update = %+v", got)
+	}
+
+	if _, err := client.Resources.Unpublish(ctx, PublishRequest{Path: "disk:/share"}); err != nil {
+		t.Fatalf("unpublish: %v", err)
+	}
+	if _, err := client.Resources.GetPublicSettings(ctx, "disk:/share"); !isNotFound(err) {
+		t.Fatalf("settings after unpublish = %v", err)
+	}
+}
+
+func TestPublicDownloadTree(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	var failed int32
+	client, err := NewClient(
+		WithOAuthToken("token"),
+		WithBaseURL(srv.URL),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithMiddleware(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				// Fail the first download of b.txt once to exercise retries.
+				if req.URL.Path == "/download" && strings.HasSuffix(req.URL.Query().Get("path"), "b.txt") && atomic.CompareAndSwapInt32(&failed, 0, 1) {
+					return &http.Response{StatusCode: http.StatusInternalServerError, Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{}, Request: req}, nil
+				}
+				return next(req)
+			}
+		}),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	srv.PutFile("disk:/share/a.txt", []byte("aaa"), time.Now())
+	srv.PutFile("disk:/share/sub/b.txt", []byte("bb"), time.Now())
+	srv.PutFile("disk:/share/sub/deeper/c.txt", []byte("c"), time.Now())
+	if _, err := client.Resources.Publish(ctx, PublishRequest{Path: "disk:/share"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	meta, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/share"})
+	if err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+
+	body, err := client.Public.OpenDownload(ctx, PublicDownloadRequest{PublicKey: meta.PublicKey, Path: "/a.txt"})
+	if err != nil {
+		t.Fatalf("open download: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	if string(data) != "aaa" {
+		t.Fatalf("public download = %q", data)
+	}
+
+	dir := t.TempDir()
+	var files int32
+	report, err := client.Public.DownloadTree(ctx, meta.PublicKey, dir, DownloadTreeConfig{
+		Concurrency: 2,
+		OnFile:      func(DownloadTreeItem) { atomic.AddInt32(&files, 1) },
+	})
+	if err != nil {
+		t.Fatalf("download tree: %v", err)
+	}
+	if len(report.Items) != 3 || files != 3 {
+		t.Fatalf("report = %+v", report.Items)
+	}
+	if !sort.SliceIsSorted(report.Items, func(i, j int) bool { return report.Items[i].Path < report.Items[j].Path }) {
+		t.Fatalf("report not sorted by path: %+v", report.Items)
+	}
+	for rel, want := range map[string]string{"a.txt": "aaa", "sub/b.txt": "bb", "sub/deeper/c.txt": "c"} {
+		got, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil || string(got) != want {
+			t.Fatalf("%s = %q, %v", rel, got, err)
+		}
+	}
+	for _, item := range report.Items {
+		if strings.HasSuffix(item.Path, "b.txt") && item.Attempts != 2 {
+			t.Fatalf("b.txt attempts = %d", item.Attempts)
+		}
+	}
+
+	// Rooting at /sub maps its files onto the copies already in dir/sub.
+	report, err = client.Public.DownloadTree(ctx, meta.PublicKey, filepath.Join(dir, "sub"), DownloadTreeConfig{Path: "/sub", SkipExisting: true})
+	if err != nil {
+		t.Fatalf("download subtree: %v", err)
+	}
+	if len(report.Items) != 2 {
+		t.Fatalf("subtree report = %+v", report.Items)
+	}
+	for _, item := range report.Items {
+		if !item.Skipped {
+			t.Fatalf("existing file downloaded again: %+v", item)
+		}
+	}
+}
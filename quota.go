@@ -0,0 +1,234 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultQuotaTTL             = time.Minute
+	defaultQuotaMonitorInterval = 5 * time.Minute
+	defaultQuotaMonitorBuffer   = 16
+)
+
+var (
+	ErrQuotaExceeded = errors.New("not enough free space on disk")
+	ErrFileTooLarge  = errors.New("file exceeds the maximum file size")
+)
+
+type QuotaCheckConfig struct {
+	// TTL bounds how long the DiskInfo used for checks is reused. Uploads
+	// made through the client are added to the cached usage in between.
+	// Defaults to one minute.
+	TTL time.Duration
+}
+
+// WithQuotaCheck makes UploadByLink and UploadInChunks compare the body size
+// with DiskInfo.MaxFileSize and the free space before sending anything,
+// failing with ErrFileTooLarge or ErrQuotaExceeded. Bodies of unknown size
+// are not checked. The check ignores space an overwritten file would free.
+func WithQuotaCheck(cfg QuotaCheckConfig) Option {
+	return func(c *config) error {
+		if cfg.TTL <= 0 {
+			cfg.TTL = defaultQuotaTTL
+		}
+		c.quotaCheck = &cfg
+		return nil
+	}
+}
+
+type quotaChecker struct {
+	client *Client
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	info    *DiskInfo
+	fetched time.Time
+}
+
+func newQuotaChecker(c *Client, cfg *QuotaCheckConfig) *quotaChecker {
+	if cfg == nil {
+		return nil
+	}
+	return &quotaChecker{client: c, ttl: cfg.TTL}
+}
+
+func (q *quotaChecker) check(ctx context.Context, size int64) error {
+	if q == nil || size < 0 {
+		return nil
+	}
+	q.mu.Lock()
+	info := q.info
+	if info == nil || time.Since(q.fetched) >= q.ttl {
+		q.mu.Unlock()
+		fresh, err := q.client.Disk.Get(ctx, DiskGetRequest{})
+		if err != nil {
+			return err
+		}
+		q.mu.Lock()
+		q.info, q.fetched = fresh, time.Now()
+		info = fresh
+	}
+	snapshot := *info
+	q.mu.Unlock()
+	return checkUploadSize(&snapshot, size)
+}
+
+// uploaded adds a finished upload to the cached usage.
+func (q *quotaChecker) uploaded(size int64) {
+	if q == nil || size <= 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.info != nil {
+		q.info.UsedSpace += size
+	}
+}
+
+func checkUploadSize(info *DiskInfo, size int64) error {
+	if info.MaxFileSize > 0 && size > info.MaxFileSize {
+		return fmt.Errorf("%w: %d bytes, limit is %d", ErrFileTooLarge, size, info.MaxFileSize)
+	}
+	if free := info.FreeSpace(); info.TotalSpace > 0 && size > free {
+		return fmt.Errorf("%w: %d bytes needed, %d free", ErrQuotaExceeded, size, free)
+	}
+	return nil
+}
+
+// FreeSpace returns TotalSpace minus UsedSpace, never below zero.
+func (d DiskInfo) FreeSpace() int64 {
+	if free := d.TotalSpace - d.UsedSpace; free > 0 {
+		return free
+	}
+	return 0
+}
+
+// UsedRatio returns UsedSpace as a fraction of TotalSpace.
+func (d DiskInfo) UsedRatio() float64 {
+	if d.TotalSpace <= 0 {
+		return 0
+	}
+	return float64(d.UsedSpace) / float64(d.TotalSpace)
+}
+
+// CheckUpload fetches DiskInfo and reports whether a file of size bytes
+// fits, returning ErrFileTooLarge or ErrQuotaExceeded when it does not.
+func (s *DiskService) CheckUpload(ctx context.Context, size int64) (err error) {
+	ctx, end := s.client.startCall(ctx, "Disk.CheckUpload")
+	defer func() { end(err) }()
+
+	info, err := s.Get(ctx, DiskGetRequest{Fields: []string{"total_space", "used_space", "max_file_size"}})
+	if err != nil {
+		return err
+	}
+	return checkUploadSize(info, size)
+}
+
+type QuotaMonitorConfig struct {
+	// Interval between checks. Defaults to five minutes.
+	Interval time.Duration
+	// Thresholds are used-space fractions, such as 0.9 for 90%. Defaults to
+	// 0.8, 0.9 and 0.95.
+	Thresholds []float64
+	// Buffer is the capacity of the Events channel. Defaults to 16.
+	Buffer int
+}
+
+// QuotaEvent reports that disk usage crossed a threshold, upwards when
+// Exceeded is set and back below it otherwise.
+type QuotaEvent struct {
+	Threshold float64
+	Exceeded  bool
+	Info      DiskInfo
+	// Err is set, and the other fields are zero, when a check failed.
+	Err error
+}
+
+// QuotaMonitor polls DiskInfo and reports threshold crossings. The first
+// check reports every threshold already exceeded.
+type QuotaMonitor struct {
+	client *Client
+	cfg    QuotaMonitorConfig
+	events chan QuotaEvent
+
+	mu       sync.Mutex
+	exceeded map[float64]bool
+}
+
+func (c *Client) NewQuotaMonitor(cfg QuotaMonitorConfig) (*QuotaMonitor, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultQuotaMonitorInterval
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaultQuotaMonitorBuffer
+	}
+	if len(cfg.Thresholds) == 0 {
+		cfg.Thresholds = []float64{0.8, 0.9, 0.95}
+	}
+	cfg.Thresholds = append([]float64(nil), cfg.Thresholds...)
+	for _, t := range cfg.Thresholds {
+		if t <= 0 || t > 1 {
+			return nil, fmt.Errorf("quota threshold %v is outside (0, 1]", t)
+		}
+	}
+	sort.Float64s(cfg.Thresholds)
+	return &QuotaMonitor{client: c, cfg: cfg, events: make(chan QuotaEvent, cfg.Buffer), exceeded: make(map[float64]bool)}, nil
+}
+
+// Events is closed when Run returns.
+func (m *QuotaMonitor) Events() <-chan QuotaEvent {
+	return m.events
+}
+
+// Run checks until ctx is done, sending events to Events. It returns
+// ctx.Err().
+func (m *QuotaMonitor) Run(ctx context.Context) error {
+	defer close(m.events)
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		events, err := m.Check(ctx)
+		if err != nil && ctx.Err() == nil {
+			events = []QuotaEvent{{Err: err}}
+		}
+		for _, e := range events {
+			select {
+			case m.events <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches DiskInfo once and returns the crossings since the previous
+// check. It may be used instead of Run to drive the monitor manually.
+func (m *QuotaMonitor) Check(ctx context.Context) ([]QuotaEvent, error) {
+	info, err := m.client.Disk.Get(ctx, DiskGetRequest{})
+	if err != nil {
+		return nil, err
+	}
+	ratio := info.UsedRatio()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var events []QuotaEvent
+	for _, t := range m.cfg.Thresholds {
+		over := ratio >= t
+		if over != m.exceeded[t] {
+			m.exceeded[t] = over
+			events = append(events, QuotaEvent{Threshold: t, Exceeded: over, Info: *info})
+		}
+	}
+	return events, nil
+}
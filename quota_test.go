@@ -0,0 +1,110 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func TestUploadQuotaPreflight(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	srv.TotalSpace = 100
+	srv.MaxFileSize = 40
+	var uploads, diskGets int
+	client, err := NewClient(
+		WithOAuthToken("token"),
+		WithBaseURL(srv.URL),
+		WithQuotaCheck(QuotaCheckConfig{TTL: time.Hour}),
+		WithHooks(Hooks{OnRequest: func(r *http.Request) {
+			switch {
+			case r.URL.Path == "/disk":
+				diskGets++
+			case r.Method == http.MethodPut:
+				uploads++
+			}
+		}}),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	srv.PutFile("disk:/existing", make([]byte, 50), time.Now())
+
+	upload := func(name string, size int) error {
+		link, err := client.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: name})
+		if err != nil {
+			return err
+		}
+		_, err = client.Uploads.UploadByLink(ctx, link, bytes.NewReader(make([]byte, size)))
+		return err
+	}
+
+	if err := upload("disk:/big", 41); !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("oversized upload = %v", err)
+	}
+	if err := upload("disk:/a", 30); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	// 80 of 100 bytes are now used according to the cached DiskInfo.
+	if err := upload("disk:/b", 30); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("upload over quota = %v", err)
+	}
+	link, err := client.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: "disk:/c"})
+	if err != nil {
+		t.Fatalf("upload link: %v", err)
+	}
+	if _, err := client.Uploads.UploadInChunks(ctx, link, bytes.NewReader(make([]byte, 25)), UploadChunkRequest{PartSize: 10}); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("chunked upload over quota = %v", err)
+	}
+	if uploads != 1 || diskGets != 1 {
+		t.Fatalf("uploads = %d disk gets = %d", uploads, diskGets)
+	}
+
+	if err := client.Disk.CheckUpload(ctx, 20); err != nil {
+		t.Fatalf("check upload: %v", err)
+	}
+	if err := client.Disk.CheckUpload(ctx, 21); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("check upload = %v", err)
+	}
+}
+
+func TestQuotaMonitorThresholds(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	srv.TotalSpace = 100
+	ctx := context.Background()
+	m, err := client.NewQuotaMonitor(QuotaMonitorConfig{Thresholds: []float64{0.9, 0.5}})
+	if err != nil {
+		t.Fatalf("new monitor: %v", err)
+	}
+
+	srv.PutFile("disk:/a", make([]byte, 60), time.Now())
+	events, err := m.Check(ctx)
+	if err != nil || len(events) != 1 || events[0].Threshold != 0.5 || !events[0].Exceeded {
+		t.Fatalf("first check = %+v, %v", events, err)
+	}
+	if events, _ := m.Check(ctx); len(events) != 0 {
+		t.Fatalf("unchanged check = %+v", events)
+	}
+
+	srv.PutFile("disk:/b", make([]byte, 35), time.Now())
+	events, _ = m.Check(ctx)
+	if len(events) != 1 || events[0].Threshold != 0.9 || events[0].Info.UsedSpace != 95 {
+		t.Fatalf("rise = %+v", events)
+	}
+
+	srv.Remove("disk:/a")
+	events, _ = m.Check(ctx)
+	if len(events) != 2 || events[0].Exceeded || events[1].Exceeded {
+		t.Fatalf("fall = %+v", events)
+	}
+
+	if _, err := client.NewQuotaMonitor(QuotaMonitorConfig{Thresholds: []float64{90}}); err == nil {
+		t.Fatal("percentage threshold accepted")
+	}
+}
@@ -0,0 +1,357 @@
+// Package retention enforces declarative clean-up rules on Disk folders and
+// the trash.
+//
+//	engine, err := retention.New(client, []retention.Rule{
+//		{Name: "backups", Root: "disk:/backups", Match: "*.tar.gz", KeepLast: 7},
+//		{Name: "logs", Root: "disk:/logs", MaxAge: 30 * 24 * time.Hour},
+//		{Name: "trash", Trash: true, MaxAge: 14 * 24 * time.Hour},
+//	}, retention.WithAudit(os.Stderr))
+//	plan, err := engine.Plan(ctx)
+//	// inspect plan.Actions, then
+//	report, err := engine.Execute(ctx, plan)
+package retention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	yadisk "github.com/grixate/yandex-disk-go-v2"
+)
+
+// Rule selects files to delete. A file is deleted when any of MaxAge,
+// KeepLast or MaxSize selects it; at least one must be set.
+type Rule struct {
+	// Name identifies the rule in plans and audit records.
+	Name string
+	// Root is the folder the rule applies to, recursively. For trash rules
+	// it limits the rule to entries deleted from below Root; empty means the
+	// whole trash.
+	Root string
+	// Trash applies the rule to the trash, using deletion time instead of
+	// modification time. Trash entries are always removed permanently.
+	Trash bool
+	// Match restricts the rule to names matching a path.Match pattern.
+	Match string
+	// MaxAge deletes files modified (or trashed) longer ago than this.
+	MaxAge time.Duration
+	// KeepLast keeps the newest N files of each folder and deletes the rest.
+	// For trash rules it keeps the newest N deleted copies of each origin
+	// path.
+	KeepLast int
+	// MaxSize deletes the oldest files until those left under Root total at
+	// most this many bytes.
+	MaxSize int64
+	// Permanently deletes files instead of moving them to the trash.
+	Permanently bool
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return errors.New("retention rule name is required")
+	}
+	if !r.Trash && r.Root == "" {
+		return fmt.Errorf("retention rule %q: root is required", r.Name)
+	}
+	if r.MaxAge <= 0 && r.KeepLast <= 0 && r.MaxSize <= 0 {
+		return fmt.Errorf("retention rule %q: set MaxAge, KeepLast or MaxSize", r.Name)
+	}
+	if r.Match != "" {
+		if _, err := path.Match(r.Match, ""); err != nil {
+			return fmt.Errorf("retention rule %q: match %q: %w", r.Name, r.Match, err)
+		}
+	}
+	return nil
+}
+
+// Action is one planned deletion.
+type Action struct {
+	Rule   string `json:"rule"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Reason string `json:"reason"`
+	// Time is the modification time, or the deletion time for trash.
+	Time        time.Time `json:"time"`
+	Trash       bool      `json:"trash,omitempty"`
+	Permanently bool      `json:"permanently,omitempty"`
+	// MD5 guards the delete against the file changing after planning.
+	MD5 string `json:"md5,omitempty"`
+}
+
+// Plan lists the deletions the rules select. Building one changes nothing,
+// so it doubles as a dry run.
+type Plan struct {
+	Created time.Time
+	Actions []Action
+}
+
+// Size returns the bytes the plan frees.
+func (p *Plan) Size() int64 {
+	var n int64
+	for _, a := range p.Actions {
+		n += a.Size
+	}
+	return n
+}
+
+type Result struct {
+	Action
+	Err error
+	// AuditErr is set when the audit record for the action could not be
+	// written.
+	AuditErr error
+}
+
+type Report struct {
+	Results []Result
+}
+
+func (r *Report) Failed() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Err != nil {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+type config struct {
+	audit io.Writer
+	now   func() time.Time
+}
+
+type Option func(*config)
+
+// WithAudit writes a JSON line per executed action to w.
+func WithAudit(w io.Writer) Option {
+	return func(c *config) {
+		c.audit = w
+	}
+}
+
+// WithClock replaces time.Now when ages are computed.
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+type Engine struct {
+	client *yadisk.Client
+	rules  []Rule
+	cfg    config
+}
+
+func New(client *yadisk.Client, rules []Rule, opts ...Option) (*Engine, error) {
+	if client == nil {
+		return nil, errors.New("client is required")
+	}
+	for _, r := range rules {
+		if err := r.validate(); err != nil {
+			return nil, err
+		}
+	}
+	cfg := config{now: time.Now}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return &Engine{client: client, rules: rules, cfg: cfg}, nil
+}
+
+// candidate is a file or trash entry a rule may delete.
+type candidate struct {
+	path  string
+	group string
+	size  int64
+	md5   string
+	time  time.Time
+}
+
+// Plan evaluates every rule. A path selected by several rules is planned
+// once, for the first rule that selects it.
+func (e *Engine) Plan(ctx context.Context) (*Plan, error) {
+	now := e.cfg.now()
+	plan := &Plan{Created: now}
+	seen := make(map[string]bool)
+	for _, rule := range e.rules {
+		var (
+			cands []candidate
+			err   error
+		)
+		if rule.Trash {
+			cands, err = e.trashCandidates(ctx, rule)
+		} else {
+			cands, err = e.folderCandidates(ctx, rule)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("retention rule %q: %w", rule.Name, err)
+		}
+		for _, a := range evaluate(rule, cands, now) {
+			if !seen[a.Path] {
+				seen[a.Path] = true
+				plan.Actions = append(plan.Actions, a)
+			}
+		}
+	}
+	return plan, nil
+}
+
+func (e *Engine) folderCandidates(ctx context.Context, rule Rule) ([]candidate, error) {
+	var out []candidate
+	err := e.client.Resources.Walk(ctx, rule.Root, func(r yadisk.Resource) error {
+		if r.Type == "dir" || !matches(rule, r.Name) {
+			return nil
+		}
+		out = append(out, candidate{path: r.Path, group: path.Dir(r.Path), size: r.Size, md5: r.MD5, time: r.Modified.Time})
+		return nil
+	})
+	return out, err
+}
+
+func (e *Engine) trashCandidates(ctx context.Context, rule Rule) ([]candidate, error) {
+	entries, err := e.client.Trash.List(ctx, yadisk.TrashFilter{OriginPrefix: rule.Root})
+	if err != nil {
+		return nil, err
+	}
+	var out []candidate
+	for _, t := range entries {
+		if !matches(rule, path.Base(t.OriginPath)) {
+			continue
+		}
+		out = append(out, candidate{path: t.Path, group: t.OriginPath, size: t.Size, time: t.Deleted.Time})
+	}
+	return out, nil
+}
+
+func matches(rule Rule, name string) bool {
+	if rule.Match == "" {
+		return true
+	}
+	ok, _ := path.Match(rule.Match, name)
+	return ok
+}
+
+// evaluate returns the deletions rule selects among cands, oldest first.
+func evaluate(rule Rule, cands []candidate, now time.Time) []Action {
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].time.After(cands[j].time) })
+	reasons := make(map[string]string)
+
+	if rule.MaxAge > 0 {
+		cutoff := now.Add(-rule.MaxAge)
+		for _, c := range cands {
+			if c.time.Before(cutoff) {
+				reasons[c.path] = "older than " + rule.MaxAge.String()
+			}
+		}
+	}
+	if rule.KeepLast > 0 {
+		kept := make(map[string]int)
+		for _, c := range cands {
+			kept[c.group]++
+			if kept[c.group] > rule.KeepLast && reasons[c.path] == "" {
+				reasons[c.path] = fmt.Sprintf("beyond newest %d", rule.KeepLast)
+			}
+		}
+	}
+	if rule.MaxSize > 0 {
+		var total int64
+		for _, c := range cands {
+			if reasons[c.path] == "" {
+				total += c.size
+			}
+		}
+		for i := len(cands) - 1; i >= 0 && total > rule.MaxSize; i-- {
+			c := cands[i]
+			if reasons[c.path] == "" {
+				reasons[c.path] = fmt.Sprintf("over size cap %d", rule.MaxSize)
+				total -= c.size
+			}
+		}
+	}
+
+	var out []Action
+	for i := len(cands) - 1; i >= 0; i-- {
+		c := cands[i]
+		if reason := reasons[c.path]; reason != "" {
+			out = append(out, Action{
+				Rule:        rule.Name,
+				Path:        c.path,
+				Size:        c.size,
+				Reason:      reason,
+				Time:        c.time,
+				Trash:       rule.Trash,
+				Permanently: rule.Permanently || rule.Trash,
+				MD5:         c.md5,
+			})
+		}
+	}
+	return out
+}
+
+// Execute carries out plan. Every action is attempted unless an audit record
+// cannot be written, which stops Execute after that action; the returned
+// error joins the failures, or is the context error if ctx ended first.
+func (e *Engine) Execute(ctx context.Context, plan *Plan) (*Report, error) {
+	report := &Report{Results: make([]Result, 0, len(plan.Actions))}
+	for _, a := range plan.Actions {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		res := Result{Action: a, Err: e.execute(ctx, a)}
+		res.AuditErr = e.writeAudit(res)
+		report.Results = append(report.Results, res)
+		if res.AuditErr != nil {
+			break
+		}
+	}
+	var errs []error
+	for _, res := range report.Results {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("delete %s: %w", res.Path, res.Err))
+		}
+		if res.AuditErr != nil {
+			errs = append(errs, fmt.Errorf("audit %s: %w", res.Path, res.AuditErr))
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+func (e *Engine) execute(ctx context.Context, a Action) error {
+	if a.Trash {
+		_, err := e.client.Trash.Empty(ctx, yadisk.TrashDeleteRequest{Path: a.Path})
+		return err
+	}
+	permanently := a.Permanently
+	_, err := e.client.Resources.Delete(ctx, yadisk.DeleteResourceRequest{Path: a.Path, MD5: a.MD5, Permanently: &permanently})
+	return err
+}
+
+type auditRecord struct {
+	Executed time.Time `json:"executed"`
+	Action
+	Error string `json:"error,omitempty"`
+}
+
+func (e *Engine) writeAudit(res Result) error {
+	if e.cfg.audit == nil {
+		return nil
+	}
+	rec := auditRecord{Executed: e.cfg.now(), Action: res.Action}
+	if res.Err != nil {
+		rec.Error = res.Err.Error()
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = e.cfg.audit.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,176 @@
+package retention
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	yadisk "github.com/grixate/yandex-disk-go-v2"
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func newTestEngine(t *testing.T, rules []Rule, opts ...Option) (*Engine, *fakedisk.Server) {
+	t.Helper()
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	client, err := yadisk.NewClient(yadisk.WithOAuthToken("token"), yadisk.WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	engine, err := New(client, rules, opts...)
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	return engine, srv
+}
+
+func planned(plan *Plan) map[string]string {
+	out := make(map[string]string)
+	for _, a := range plan.Actions {
+		out[a.Path] = a.Rule
+	}
+	return out
+}
+
+func TestPlanAndExecute(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	var audit bytes.Buffer
+	engine, srv := newTestEngine(t, []Rule{
+		{Name: "backups", Root: "disk:/backups", Match: "*.tar", KeepLast: 2},
+		{Name: "logs", Root: "disk:/logs", MaxAge: 30 * day, Permanently: true},
+		{Name: "cache", Root: "disk:/cache", MaxSize: 10},
+		{Name: "trash", Trash: true, MaxAge: 7 * day},
+	}, WithClock(func() time.Time { return now }), WithAudit(&audit))
+
+	for i, age := range []time.Duration{1, 2, 3, 4} {
+		srv.PutFile("disk:/backups/b"+string(rune('0'+i))+".tar", []byte("b"), now.Add(-age*day))
+	}
+	srv.PutFile("disk:/backups/notes.txt", []byte("n"), now.Add(-100*day))
+	srv.PutFile("disk:/logs/old.log", []byte("o"), now.Add(-40*day))
+	srv.PutFile("disk:/logs/new.log", []byte("n"), now.Add(-day))
+	srv.PutFile("disk:/cache/a", make([]byte, 6), now.Add(-3*day))
+	srv.PutFile("disk:/cache/b", make([]byte, 6), now.Add(-2*day))
+	srv.PutFile("disk:/cache/c", make([]byte, 4), now.Add(-day))
+	oldTrash := srv.PutTrash("disk:/gone.txt", []byte("g"), now.Add(-10*day))
+	srv.PutTrash("disk:/recent.txt", []byte("r"), now.Add(-day))
+
+	plan, err := engine.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("plan: %v", err)
+	}
+	want := map[string]string{
+		"disk:/backups/b2.tar": "backups",
+		"disk:/backups/b3.tar": "backups",
+		"disk:/logs/old.log":   "logs",
+		"disk:/cache/a":        "cache",
+		oldTrash:               "trash",
+	}
+	got := planned(plan)
+	if len(got) != len(want) {
+		t.Fatalf("plan = %v", got)
+	}
+	for p, rule := range want {
+		if got[p] != rule {
+			t.Fatalf("plan = %v, missing %s", got, p)
+		}
+	}
+	if !srv.Exists("disk:/backups/b3.tar") {
+		t.Fatal("planning deleted a file")
+	}
+
+	report, err := engine.Execute(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("report = %+v", report.Results)
+	}
+	if srv.Exists("disk:/backups/b3.tar") || !srv.Trashed("disk:/backups/b3.tar") {
+		t.Fatal("backup not moved to trash")
+	}
+	if srv.Exists("disk:/logs/old.log") || srv.Trashed("disk:/logs/old.log") {
+		t.Fatal("log not deleted permanently")
+	}
+	if srv.Trashed("disk:/gone.txt") || !srv.Trashed("disk:/recent.txt") {
+		t.Fatal("trash rule purged the wrong entries")
+	}
+
+	lines := 0
+	sc := bufio.NewScanner(&audit)
+	for sc.Scan() {
+		var rec map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil || rec["rule"] == nil || rec["path"] == nil {
+			t.Fatalf("audit line %q: %v", sc.Text(), err)
+		}
+		lines++
+	}
+	if lines != len(want) {
+		t.Fatalf("audit lines = %d", lines)
+	}
+}
+
+func TestExecuteSkipsChangedFiles(t *testing.T) {
+	engine, srv := newTestEngine(t, []Rule{{Name: "logs", Root: "disk:/logs", MaxAge: time.Hour}})
+	srv.PutFile("disk:/logs/a.log", []byte("v1"), time.Now().Add(-2*time.Hour))
+	plan, err := engine.Plan(context.Background())
+	if err != nil || len(plan.Actions) != 1 {
+		t.Fatalf("plan = %+v, %v", plan, err)
+	}
+	srv.PutFile("disk:/logs/a.log", []byte("v2"), time.Now())
+	report, err := engine.Execute(context.Background(), plan)
+	if err == nil || len(report.Failed()) != 1 || !srv.Exists("disk:/logs/a.log") {
+		t.Fatalf("changed file deleted: %v", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestExecuteStopsWhenAuditFails(t *testing.T) {
+	engine, srv := newTestEngine(t, []Rule{{Name: "logs", Root: "disk:/logs", MaxAge: time.Hour}}, WithAudit(failingWriter{}))
+	srv.PutFile("disk:/logs/a.log", []byte("a"), time.Now().Add(-2*time.Hour))
+	srv.PutFile("disk:/logs/b.log", []byte("b"), time.Now().Add(-3*time.Hour))
+	plan, err := engine.Plan(context.Background())
+	if err != nil || len(plan.Actions) != 2 {
+		t.Fatalf("plan = %+v, %v", plan, err)
+	}
+	report, err := engine.Execute(context.Background(), plan)
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("execute = %v", err)
+	}
+	if len(report.Results) != 1 || report.Results[0].AuditErr == nil {
+		t.Fatalf("results = %+v", report.Results)
+	}
+	if srv.Exists(report.Results[0].Path) {
+		t.Fatal("first action not carried out")
+	}
+	if !srv.Exists(plan.Actions[1].Path) {
+		t.Fatal("execution continued without an audit trail")
+	}
+}
+
+func TestRuleValidation(t *testing.T) {
+	client, err := yadisk.NewClient(yadisk.WithOAuthToken("token"))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	for _, r := range []Rule{
+		{Root: "disk:/a", MaxAge: time.Hour},
+		{Name: "no root", MaxAge: time.Hour},
+		{Name: "no limit", Root: "disk:/a"},
+		{Name: "bad glob", Root: "disk:/a", Match: "[", KeepLast: 1},
+	} {
+		if _, err := New(client, []Rule{r}); err == nil {
+			t.Fatalf("rule %+v accepted", r)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrOutsideScope is returned by scoped clients for paths that would leave
+// the scope root.
+var ErrOutsideScope = errors.New("path is outside the client scope")
+
+// WithAppFolder scopes the client to the application folder, as required
+// for tokens with the app_folder permission. It behaves like
+// Client.Scoped("app:/").
+func WithAppFolder() Option {
+	return func(c *config) error {
+		c.scope = "app:/"
+		return nil
+	}
+}
+
+// Scoped returns a view of the client confined to root. Paths passed to the
+// view are taken relative to root, so "disk:/a", "/a" and "a" all address
+// root/a, and paths containing ".." fail with ErrOutsideScope. Resource
+// paths in results are rewritten back to the same relative "disk:/a" form.
+// Scoping a scoped client nests the roots.
+//
+// Flat listings drop files outside root, so their pages may be shorter than
+// requested; IterateFiles walks root instead. Trash paths are not rewritten.
+// Trash listings keep only entries deleted from below root, with their origin
+// paths made relative; other trash calls look up the entry's origin first and
+// fail with ErrOutsideScope outside root, as does emptying the whole trash.
+// Public resources saved without a save path go to root.
+//
+// The view shares the transport, options and worker of c, and has its own
+// metadata cache when c has one. Close c, not the view.
+func (c *Client) Scoped(root string) (*Client, error) {
+	r, err := c.scope.mapIn(root)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParsePath(r)
+	if err != nil {
+		return nil, err
+	}
+	if p.Scheme() == SchemeTrash {
+		return nil, fmt.Errorf("scope root %q: trash paths cannot be scope roots", root)
+	}
+
+	v := &Client{
+		transport:  c.transport,
+		retry:      c.retry,
+		hooks:      c.hooks,
+		log:        c.log,
+		quota:      c.quota,
+		workerCfg:  c.workerCfg,
+		randSource: rand.New(rand.NewSource(time.Now().UnixNano())),
+
+		uploadLimiter:   c.uploadLimiter,
+		downloadLimiter: c.downloadLimiter,
+	}
+	v.scope = newPathScope(v, p)
+	v.cache = c.cache.view(v)
+	v.initServices()
+	v.Worker = c.Worker
+	return v, nil
+}
+
+type unscopedKey struct{}
+
+// pathScope maps request paths into root and result paths back out of it.
+type pathScope struct {
+	client *Client
+	root   Path
+
+	mu sync.Mutex
+	// diskRoot is root in the "disk:" form results use. For app roots it
+	// is looked up on first use.
+	diskRoot Path
+}
+
+func newPathScope(c *Client, root Path) *pathScope {
+	s := &pathScope{client: c, root: root}
+	if root.Scheme() == SchemeDisk {
+		s.diskRoot = root
+	}
+	return s
+}
+
+// scopedParams lists the query parameters holding Disk paths per endpoint.
+// Responses of endpoints not listed here are passed through untouched.
+func scopedParams(endpoint string) ([]string, bool) {
+	switch {
+	case strings.HasPrefix(endpoint, "/disk/resources"):
+		return []string{"path", "from"}, true
+	case endpoint == "/disk/public/resources/save-to-disk":
+		return []string{"save_path"}, true
+	case endpoint == "/disk/public/resources/public-settings":
+		return []string{"path"}, true
+	case strings.HasPrefix(endpoint, "/disk/trash/resources"):
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// forRequest returns the scope to apply to a request to endpoint, or nil.
+func (s *pathScope) forRequest(ctx context.Context, endpoint string) (*pathScope, []string) {
+	if s == nil || ctx.Value(unscopedKey{}) != nil {
+		return nil, nil
+	}
+	params, ok := scopedParams(endpoint)
+	if !ok {
+		return nil, nil
+	}
+	return s, params
+}
+
+func (s *pathScope) mapQuery(ctx context.Context, method, endpoint string, query url.Values, params []string) (url.Values, error) {
+	if err := s.resolve(ctx); err != nil {
+		return nil, err
+	}
+	out := make(url.Values, len(query))
+	for k, v := range query {
+		out[k] = append([]string(nil), v...)
+	}
+	for _, k := range params {
+		for i, v := range out[k] {
+			mapped, err := s.mapIn(v)
+			if err != nil {
+				return nil, err
+			}
+			out[k][i] = mapped
+		}
+	}
+	switch {
+	case strings.HasPrefix(endpoint, "/disk/trash/resources"):
+		if err := s.checkTrash(ctx, method, out.Get("path")); err != nil {
+			return nil, err
+		}
+	case endpoint == "/disk/public/resources/save-to-disk":
+		// The API saves into the Downloads folder by default.
+		if out.Get("save_path") == "" {
+			out.Set("save_path", s.root.String())
+		}
+	}
+	return out, nil
+}
+
+// checkTrash confines a trash request for p to entries deleted from below
+// root. The trash root may be listed, as listings are filtered, but not
+// emptied.
+func (s *pathScope) checkTrash(ctx context.Context, method, p string) error {
+	parsed, err := ParsePath(p)
+	if p == "" || err == nil && parsed.IsRoot() {
+		if method == http.MethodGet {
+			return nil
+		}
+		return fmt.Errorf("%w: the whole trash", ErrOutsideScope)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOutsideScope, err)
+	}
+
+	// Only top-level trash entries record where they were deleted from.
+	entry := strings.SplitN(strings.TrimPrefix(parsed.p, "/"), "/", 2)[0]
+	q := url.Values{"path": {TrashPath(entry).String()}, "fields": {"origin_path"}}
+	var r TrashResource
+	ctx = context.WithValue(ctx, unscopedKey{}, true)
+	if _, err := s.client.doJSON(ctx, http.MethodGet, "/disk/trash/resources", q, nil, &r, http.StatusOK); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if r.OriginPath == "" || s.outside(r.OriginPath) {
+		return fmt.Errorf("%w: %s", ErrOutsideScope, p)
+	}
+	return nil
+}
+
+// mapIn resolves a caller path against root. Trash paths pass through.
+func (s *pathScope) mapIn(p string) (string, error) {
+	if s == nil || p == "" {
+		return p, nil
+	}
+	parsed, err := ParsePath(p)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOutsideScope, err)
+	}
+	switch parsed.Scheme() {
+	case SchemeTrash:
+		return p, nil
+	case SchemeApp:
+		if s.root.Scheme() != SchemeApp {
+			return "", fmt.Errorf("%w: %s", ErrOutsideScope, p)
+		}
+	}
+	return s.root.Join(parsed.p).String(), nil
+}
+
+// resolve looks up the disk path of an app root. A missing application
+// folder is not an error; the lookup is retried on the next request.
+func (s *pathScope) resolve(ctx context.Context) error {
+	if s.root.Scheme() != SchemeApp {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.diskRoot.IsZero() {
+		return nil
+	}
+	q := url.Values{"path": {s.root.String()}, "fields": {"path"}}
+	var r Resource
+	ctx = context.WithValue(ctx, unscopedKey{}, true)
+	if _, err := s.client.doJSON(ctx, http.MethodGet, "/disk/resources", q, nil, &r, http.StatusOK); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("resolve scope root %s: %w", s.root, err)
+	}
+	p, err := ParsePath(r.Path)
+	if err != nil {
+		return fmt.Errorf("resolve scope root %s: %w", s.root, err)
+	}
+	s.diskRoot = p
+	return nil
+}
+
+// mapOut returns p relative to root in "disk:" form, and false when p is
+// not below root.
+func (s *pathScope) mapOut(p string) (string, bool) {
+	parsed, err := ParsePath(p)
+	if err != nil {
+		return p, false
+	}
+	s.mu.Lock()
+	diskRoot := s.diskRoot
+	s.mu.Unlock()
+	for _, root := range []Path{s.root, diskRoot} {
+		if root.IsZero() {
+			continue
+		}
+		if rel, err := root.Rel(parsed); err == nil {
+			return DiskPath(rel).String(), true
+		}
+	}
+	return p, false
+}
+
+// outside reports whether p is a disk or app path outside root.
+func (s *pathScope) outside(p string) bool {
+	parsed, err := ParsePath(p)
+	if err != nil || parsed.Scheme() == SchemeTrash {
+		return false
+	}
+	_, ok := s.mapOut(p)
+	return !ok
+}
+
+// mapBody rewrites the paths of the resources in a JSON response and drops
+// list items outside root.
+func (s *pathScope) mapBody(b []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return b, nil
+	}
+	s.mapResource(v)
+	return json.Marshal(v)
+}
+
+// mapResource rewrites "path" and "origin_path" of a resource or listing
+// object, then of its "_embedded" listing and its "items". Other fields,
+// custom properties included, are left alone.
+func (s *pathScope) mapResource(v any) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return
+	}
+	for _, k := range []string{"path", "origin_path"} {
+		if str, ok := m[k].(string); ok {
+			m[k], _ = s.mapOut(str)
+		}
+	}
+	s.mapResource(m["_embedded"])
+	if items, ok := m["items"].([]any); ok {
+		m["items"] = s.mapItems(items)
+	}
+}
+
+// mapItems drops the items whose path, or whose origin for trash entries,
+// is outside root.
+func (s *pathScope) mapItems(items []any) []any {
+	kept := items[:0]
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			if p, ok := m["path"].(string); ok && s.outside(p) {
+				continue
+			}
+			if p, ok := m["origin_path"].(string); ok && s.outside(p) {
+				continue
+			}
+		}
+		s.mapResource(item)
+		kept = append(kept, item)
+	}
+	return kept
+}
@@ -0,0 +1,216 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func TestScopedClient(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile("disk:/tenants/a/docs/x.txt", []byte("x"), time.Now())
+	srv.PutFile("disk:/tenants/b/secret.txt", []byte("s"), time.Now())
+	srv.PutFile("disk:/root.txt", []byte("r"), time.Now())
+
+	tenant, err := client.Scoped("disk:/tenants/a")
+	if err != nil {
+		t.Fatalf("scoped: %v", err)
+	}
+
+	dir, err := tenant.Resources.GetMeta(ctx, ResourceGetRequest{Path: "/docs"})
+	if err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+	if dir.Path != "disk:/docs" || len(dir.Embedded.Items) != 1 || dir.Embedded.Items[0].Path != "disk:/docs/x.txt" {
+		t.Fatalf("dir = %s %+v", dir.Path, dir.Embedded.Items)
+	}
+
+	link, err := tenant.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: "new.txt"})
+	if err != nil {
+		t.Fatalf("upload link: %v", err)
+	}
+	if _, err := tenant.Uploads.UploadByLink(ctx, link, bytes.NewReader([]byte("n"))); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if _, err := tenant.Resources.Move(ctx, CopyMoveRequest{From: "disk:/docs/x.txt", Path: "disk:/y.txt"}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if !srv.Exists("disk:/tenants/a/new.txt") || !srv.Exists("disk:/tenants/a/y.txt") || srv.Exists("disk:/tenants/a/docs/x.txt") {
+		t.Fatal("paths were not mapped into the scope")
+	}
+
+	for _, p := range []string{"../b/secret.txt", "docs/../../b", "app:/x"} {
+		if _, err := tenant.Resources.GetMeta(ctx, ResourceGetRequest{Path: p}); !errors.Is(err, ErrOutsideScope) {
+			t.Fatalf("get %q = %v", p, err)
+		}
+	}
+
+	list, err := tenant.Resources.ListAllFiles(ctx, FlatFilesRequest{})
+	if err != nil {
+		t.Fatalf("list files: %v", err)
+	}
+	for _, r := range list.Items {
+		if r.Path != "disk:/new.txt" && r.Path != "disk:/y.txt" {
+			t.Fatalf("flat listing leaked %s", r.Path)
+		}
+	}
+	it := tenant.Resources.IterateFiles(ctx, FlatFilesRequest{})
+	n := 0
+	for it.Next() {
+		n++
+	}
+	if it.Err() != nil || n != 2 {
+		t.Fatalf("iterated %d files: %v", n, it.Err())
+	}
+
+	nested, err := tenant.Scoped("docs")
+	if err != nil {
+		t.Fatalf("nested scope: %v", err)
+	}
+	if _, err := nested.Resources.CreateFolder(ctx, CreateFolderRequest{Path: "sub"}); err != nil {
+		t.Fatalf("create folder: %v", err)
+	}
+	if !srv.Exists("disk:/tenants/a/docs/sub") {
+		t.Fatal("nested scope not applied")
+	}
+	if _, err := tenant.Scoped("../b"); !errors.Is(err, ErrOutsideScope) {
+		t.Fatalf("escaping scope = %v", err)
+	}
+}
+
+func TestWithAppFolder(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	client, err := NewClient(WithOAuthToken("token"), WithBaseURL(srv.URL), WithAppFolder())
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	srv.PutFile(fakedisk.AppFolder+"/a.txt", []byte("a"), time.Now())
+
+	r, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "/a.txt"})
+	if err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+	if r.Path != "disk:/a.txt" {
+		t.Fatalf("path = %s", r.Path)
+	}
+	if _, err := client.Resources.CreateFolder(ctx, CreateFolderRequest{Path: "app:/sub"}); err != nil {
+		t.Fatalf("create folder: %v", err)
+	}
+	if !srv.Exists(fakedisk.AppFolder + "/sub") {
+		t.Fatal("folder not created in the application folder")
+	}
+}
+
+func TestScopedClientTrash(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	mine := srv.PutTrash("disk:/tenants/a/old.txt", []byte("o"), time.Now())
+	theirs := srv.PutTrash("disk:/tenants/b/secret.txt", []byte("s"), time.Now())
+
+	tenant, err := client.Scoped("disk:/tenants/a")
+	if err != nil {
+		t.Fatalf("scoped: %v", err)
+	}
+	list, err := tenant.Trash.GetMeta(ctx, ResourceGetRequest{Path: "trash:/"})
+	if err != nil {
+		t.Fatalf("list trash: %v", err)
+	}
+	if len(list.Embedded.Items) != 1 || list.Embedded.Items[0].OriginPath != "disk:/old.txt" {
+		t.Fatalf("trash listing = %+v", list.Embedded.Items)
+	}
+
+	for name, call := range map[string]func() error{
+		"get": func() error {
+			_, err := tenant.Trash.GetMeta(ctx, ResourceGetRequest{Path: theirs})
+			return err
+		},
+		"restore": func() error {
+			_, err := tenant.Trash.Restore(ctx, TrashRestoreRequest{Path: theirs})
+			return err
+		},
+		"delete": func() error {
+			_, err := tenant.Trash.Empty(ctx, TrashDeleteRequest{Path: theirs})
+			return err
+		},
+		"empty": func() error {
+			_, err := tenant.Trash.Empty(ctx, TrashDeleteRequest{})
+			return err
+		},
+	} {
+		if err := call(); !errors.Is(err, ErrOutsideScope) {
+			t.Fatalf("%s = %v", name, err)
+		}
+	}
+	if !srv.Trashed("disk:/tenants/b/secret.txt") || !srv.Trashed("disk:/tenants/a/old.txt") {
+		t.Fatal("trash changed outside the scope")
+	}
+
+	if _, err := tenant.Trash.Restore(ctx, TrashRestoreRequest{Path: mine}); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if !srv.Exists("disk:/tenants/a/old.txt") {
+		t.Fatal("entry not restored")
+	}
+}
+
+func TestScopedSaveToDiskDefaultsToRoot(t *testing.T) {
+	var savePath string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		savePath = r.URL.Query().Get("save_path")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"href":"x","method":"GET","templated":false}`)
+	})
+	tenant, err := client.Scoped("disk:/tenants/a")
+	if err != nil {
+		t.Fatalf("scoped: %v", err)
+	}
+	for _, tc := range []struct{ in, want string }{
+		{"", "disk:/tenants/a"},
+		{"inbox", "disk:/tenants/a/inbox"},
+	} {
+		if _, err := tenant.Public.SaveToDisk(context.Background(), PublicSaveRequest{PublicKey: "k", SavePath: tc.in}); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+		if savePath != tc.want {
+			t.Fatalf("save_path for %q = %q want %q", tc.in, savePath, tc.want)
+		}
+	}
+}
+
+func TestScopedClientLeavesCustomPropertiesAlone(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile("disk:/tenants/a/x.txt", []byte("x"), time.Now())
+	props := map[string]any{
+		"link":  map[string]any{"path": "disk:/tenants/a/x.txt"},
+		"items": []any{map[string]any{"path": "disk:/elsewhere"}},
+	}
+	if _, err := client.Resources.UpdateMeta(ctx, ResourceUpdateRequest{Path: "disk:/tenants/a/x.txt", CustomProperties: props}); err != nil {
+		t.Fatalf("update meta: %v", err)
+	}
+
+	tenant, err := client.Scoped("disk:/tenants/a")
+	if err != nil {
+		t.Fatalf("scoped: %v", err)
+	}
+	r, err := tenant.Resources.GetMeta(ctx, ResourceGetRequest{Path: "x.txt"})
+	if err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+	if r.Path != "disk:/x.txt" {
+		t.Fatalf("path = %s", r.Path)
+	}
+	if !reflect.DeepEqual(r.CustomProperties, props) {
+		t.Fatalf("custom properties = %v", r.CustomProperties)
+	}
+}
@@ -0,0 +1,234 @@
+package yadisk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// FileQuery filters the flat file listing client-side, since the API only
+// filters it by media type. Build one with Resources.Query:
+//
+//	q := client.Resources.Query().
+//		MediaType("image").
+//		Ext(".jpg", ".png").
+//		SizeBetween(1<<20, 0).
+//		Glob("/photos/**").
+//		PropertyEquals("album", "2024")
+//	err := q.Each(ctx, func(r yadisk.Resource) error {
+//		return nil
+//	})
+//
+// Builder methods add conditions that must all hold.
+type FileQuery struct {
+	s     *ResourcesService
+	req   FlatFilesRequest
+	preds []func(*Resource) bool
+	limit int
+	err   error
+}
+
+func (s *ResourcesService) Query() *FileQuery {
+	return &FileQuery{s: s}
+}
+
+// MediaType keeps files of any of the given API media types, such as
+// "image" or "document". The API applies this filter server-side.
+func (q *FileQuery) MediaType(types ...string) *FileQuery {
+	q.req.MediaType = strings.Join(types, ",")
+	return q
+}
+
+// Ext keeps files whose name ends in any of exts, compared case-insensitively.
+// The leading dot is optional.
+func (q *FileQuery) Ext(exts ...string) *FileQuery {
+	want := make(map[string]bool, len(exts))
+	for _, e := range exts {
+		want["."+strings.ToLower(strings.TrimPrefix(e, "."))] = true
+	}
+	return q.Where(func(r *Resource) bool {
+		return want[strings.ToLower(path.Ext(r.Name))]
+	})
+}
+
+// SizeBetween keeps files of at least min and at most max bytes. A max of 0
+// leaves the upper bound open.
+func (q *FileQuery) SizeBetween(min, max int64) *FileQuery {
+	return q.Where(func(r *Resource) bool {
+		return r.Size >= min && (max <= 0 || r.Size <= max)
+	})
+}
+
+// CreatedBetween keeps files created in [from, to). A zero bound is open.
+func (q *FileQuery) CreatedBetween(from, to time.Time) *FileQuery {
+	return q.Where(func(r *Resource) bool { return inTimeRange(r.Created.Time, from, to) })
+}
+
+// ModifiedBetween keeps files modified in [from, to). A zero bound is open.
+func (q *FileQuery) ModifiedBetween(from, to time.Time) *FileQuery {
+	return q.Where(func(r *Resource) bool { return inTimeRange(r.Modified.Time, from, to) })
+}
+
+// Glob keeps files whose path, without the disk: prefix, matches pattern.
+// Segments use path.Match syntax and "**" matches any number of segments.
+// A pattern without a slash is matched against the file name.
+func (q *FileQuery) Glob(pattern string) *FileQuery {
+	for _, seg := range strings.Split(pattern, "/") {
+		if _, err := path.Match(seg, ""); err != nil {
+			q.setErr(fmt.Errorf("glob %q: %w", pattern, err))
+			return q
+		}
+	}
+	if !strings.Contains(pattern, "/") {
+		return q.Where(func(r *Resource) bool {
+			ok, _ := path.Match(pattern, r.Name)
+			return ok
+		})
+	}
+	want := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	return q.Where(func(r *Resource) bool {
+		return globSegments(want, strings.Split(strings.TrimPrefix(stripDiskScheme(r.Path), "/"), "/"))
+	})
+}
+
+// Property keeps files whose custom property key is set and satisfies fn.
+// Values are as decoded from JSON: numbers are float64.
+func (q *FileQuery) Property(key string, fn func(v any) bool) *FileQuery {
+	return q.Where(func(r *Resource) bool {
+		v, ok := r.CustomProperties[key]
+		return ok && fn(v)
+	})
+}
+
+func (q *FileQuery) HasProperty(key string) *FileQuery {
+	return q.Property(key, func(any) bool { return true })
+}
+
+// PropertyEquals keeps files whose custom property key encodes to the same
+// JSON as want, so PropertyEquals("rating", 5) matches a stored 5.0.
+func (q *FileQuery) PropertyEquals(key string, want any) *FileQuery {
+	wantJSON, err := json.Marshal(want)
+	if err != nil {
+		q.setErr(fmt.Errorf("property %q: %w", key, err))
+		return q
+	}
+	return q.Property(key, func(v any) bool {
+		got, err := json.Marshal(v)
+		return err == nil && string(got) == string(wantJSON)
+	})
+}
+
+// Where adds an arbitrary condition.
+func (q *FileQuery) Where(fn func(*Resource) bool) *FileQuery {
+	q.preds = append(q.preds, fn)
+	return q
+}
+
+// Limit stops the query after n matches. Zero means no limit.
+func (q *FileQuery) Limit(n int) *FileQuery {
+	q.limit = n
+	return q
+}
+
+// PageSize sets how many files each listing request fetches.
+func (q *FileQuery) PageSize(n int) *FileQuery {
+	q.req.Limit = &n
+	return q
+}
+
+func (q *FileQuery) setErr(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+func (q *FileQuery) match(r *Resource) bool {
+	for _, p := range q.preds {
+		if !p(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Each calls fn for every matching file as pages are fetched. Returning
+// fs.SkipAll from fn stops the query without an error; no further pages are
+// requested once it stops.
+func (q *FileQuery) Each(ctx context.Context, fn func(Resource) error) (err error) {
+	ctx, end := q.s.client.startCall(ctx, "Resources.Query")
+	defer func() { end(err) }()
+
+	if q.err != nil {
+		return q.err
+	}
+	if fn == nil {
+		return errors.New("query func is required")
+	}
+	n := 0
+	it := q.s.IterateFiles(ctx, q.req)
+	for (q.limit <= 0 || n < q.limit) && it.Next() {
+		r := it.Resource()
+		if !q.match(&r) {
+			continue
+		}
+		n++
+		if err := fn(r); err != nil {
+			if errors.Is(err, fs.SkipAll) {
+				return nil
+			}
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// All collects every matching file.
+func (q *FileQuery) All(ctx context.Context) ([]Resource, error) {
+	var out []Resource
+	err := q.Each(ctx, func(r Resource) error {
+		out = append(out, r)
+		return nil
+	})
+	return out, err
+}
+
+// First returns the first matching file, or false when none matches.
+func (q *FileQuery) First(ctx context.Context) (Resource, bool, error) {
+	var out Resource
+	found := false
+	err := q.Each(ctx, func(r Resource) error {
+		out, found = r, true
+		return fs.SkipAll
+	})
+	return out, found, err
+}
+
+func inTimeRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || t.Before(to))
+}
+
+func globSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if globSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,99 @@
+package yadisk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func queryPaths(t *testing.T, q *FileQuery) []string {
+	t.Helper()
+	res, err := q.All(context.Background())
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	var out []string
+	for _, r := range res {
+		out = append(out, r.Path)
+	}
+	return out
+}
+
+func TestFileQueryFilters(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	old := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	srv.PutFile("disk:/photos/2024/a.JPG", make([]byte, 100), recent)
+	srv.PutFile("disk:/photos/2023/b.png", make([]byte, 10), old)
+	srv.PutFile("disk:/photos/c.txt", []byte("c"), recent)
+	srv.PutFile("disk:/docs/d.pdf", make([]byte, 50), recent)
+	if _, err := client.Resources.UpdateProperties(ctx, "disk:/docs/d.pdf", map[string]any{"rating": 5}); err != nil {
+		t.Fatalf("properties: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		q    *FileQuery
+		want []string
+	}{
+		{"media type", client.Resources.Query().MediaType("image"), []string{"disk:/photos/2023/b.png", "disk:/photos/2024/a.JPG"}},
+		{"ext", client.Resources.Query().Ext("jpg", ".pdf"), []string{"disk:/docs/d.pdf", "disk:/photos/2024/a.JPG"}},
+		{"size", client.Resources.Query().SizeBetween(10, 50), []string{"disk:/docs/d.pdf", "disk:/photos/2023/b.png"}},
+		{"modified", client.Resources.Query().ModifiedBetween(time.Time{}, recent), []string{"disk:/photos/2023/b.png"}},
+		{"glob", client.Resources.Query().Glob("/photos/**/*.*g"), []string{"disk:/photos/2023/b.png"}},
+		{"glob name", client.Resources.Query().Glob("*.txt"), []string{"disk:/photos/c.txt"}},
+		{"property", client.Resources.Query().PropertyEquals("rating", 5), []string{"disk:/docs/d.pdf"}},
+		{"combined", client.Resources.Query().Glob("/photos/**").SizeBetween(50, 0), []string{"disk:/photos/2024/a.JPG"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := queryPaths(t, tc.q)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("got %v, want %v", got, tc.want)
+				}
+			}
+		})
+	}
+
+	if _, err := client.Resources.Query().Glob("[").All(ctx); err == nil {
+		t.Fatal("bad glob accepted")
+	}
+}
+
+func TestFileQueryStopsEarly(t *testing.T) {
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+	pages := 0
+	client, err := NewClient(WithOAuthToken("token"), WithBaseURL(srv.URL), WithHooks(Hooks{OnRequest: func(r *http.Request) {
+		if r.URL.Path == "/disk/resources/files" {
+			pages++
+		}
+	}}))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	for _, p := range []string{"a", "b", "c", "d", "e", "f"} {
+		srv.PutFile("disk:/"+p+".txt", []byte(p), time.Now())
+	}
+
+	r, ok, err := client.Resources.Query().PageSize(2).Glob("*.txt").First(context.Background())
+	if err != nil || !ok || r.Name != "a.txt" {
+		t.Fatalf("first = %v %v %v", r.Name, ok, err)
+	}
+	if pages != 1 {
+		t.Fatalf("first fetched %d pages", pages)
+	}
+
+	pages = 0
+	got := queryPaths(t, client.Resources.Query().PageSize(2).Limit(3))
+	if len(got) != 3 || pages != 2 {
+		t.Fatalf("limit 3 got %v over %d pages", got, pages)
+	}
+}
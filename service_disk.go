@@ -10,12 +10,15 @@ type DiskService struct {
 	client *Client
 }
 
-func (s *DiskService) Get(ctx context.Context, req DiskGetRequest) (*DiskInfo, error) {
+func (s *DiskService) Get(ctx context.Context, req DiskGetRequest) (_ *DiskInfo, err error) {
+	ctx, end := s.client.startCall(ctx, "Disk.Get")
+	defer func() { end(err) }()
+
 	q := url.Values{}
 	addCSV(q, "fields", req.Fields)
 
 	out := new(DiskInfo)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -11,7 +11,10 @@ type OperationsService struct {
 	client *Client
 }
 
-func (s *OperationsService) GetStatus(ctx context.Context, req OperationStatusRequest) (*OperationStatus, error) {
+func (s *OperationsService) GetStatus(ctx context.Context, req OperationStatusRequest) (_ *OperationStatus, err error) {
+	ctx, end := s.client.startCall(ctx, "Operations.GetStatus")
+	defer func() { end(err) }()
+
 	if req.OperationID == "" {
 		return nil, errors.New("operation_id is required")
 	}
@@ -19,7 +22,7 @@ func (s *OperationsService) GetStatus(ctx context.Context, req OperationStatusRe
 	addCSV(q, "fields", req.Fields)
 
 	out := new(OperationStatus)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/operations/"+req.OperationID, q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/operations/"+req.OperationID, q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
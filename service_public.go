@@ -3,6 +3,7 @@ package yadisk
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/url"
 )
@@ -11,7 +12,10 @@ type PublicService struct {
 	client *Client
 }
 
-func (s *PublicService) GetMeta(ctx context.Context, req PublicResourceRequest) (*PublicResource, error) {
+func (s *PublicService) GetMeta(ctx context.Context, req PublicResourceRequest) (_ *PublicResource, err error) {
+	ctx, end := s.client.startCall(ctx, "Public.GetMeta")
+	defer func() { end(err) }()
+
 	if req.PublicKey == "" {
 		return nil, errors.New("public_key is required")
 	}
@@ -26,14 +30,17 @@ func (s *PublicService) GetMeta(ctx context.Context, req PublicResourceRequest)
 	addString(q, "sort", req.Sort)
 
 	out := new(PublicResource)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/public/resources", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/public/resources", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *PublicService) GetDownloadURL(ctx context.Context, req PublicDownloadRequest) (*Link, error) {
+func (s *PublicService) GetDownloadURL(ctx context.Context, req PublicDownloadRequest) (_ *Link, err error) {
+	ctx, end := s.client.startCall(ctx, "Public.GetDownloadURL")
+	defer func() { end(err) }()
+
 	if req.PublicKey == "" {
 		return nil, errors.New("public_key is required")
 	}
@@ -43,14 +50,31 @@ func (s *PublicService) GetDownloadURL(ctx context.Context, req PublicDownloadRe
 	addString(q, "path", req.Path)
 
 	out := new(Link)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/public/resources/download", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/public/resources/download", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *PublicService) SaveToDisk(ctx context.Context, req PublicSaveRequest) (ActionResult, error) {
+// OpenDownload streams a public file, or the file at req.Path inside a public
+// folder.
+func (s *PublicService) OpenDownload(ctx context.Context, req PublicDownloadRequest, opts ...TransferOption) (_ io.ReadCloser, err error) {
+	ctx, end := s.client.startCall(ctx, "Public.OpenDownload")
+	defer func() { end(err) }()
+
+	link, err := s.GetDownloadURL(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.client.openHref(ctx, link, opts)
+}
+
+func (s *PublicService) SaveToDisk(ctx context.Context, req PublicSaveRequest) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Public.SaveToDisk")
+	defer func() { end(err) }()
+	defer s.client.cache.purge()
+
 	if req.PublicKey == "" {
 		return ActionResult{}, errors.New("public_key is required")
 	}
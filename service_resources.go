@@ -11,21 +11,32 @@ type ResourcesService struct {
 	client *Client
 }
 
-func (s *ResourcesService) GetMeta(ctx context.Context, req ResourceGetRequest) (*Resource, error) {
+func (s *ResourcesService) GetMeta(ctx context.Context, req ResourceGetRequest) (_ *Resource, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.GetMeta")
+	defer func() { end(err) }()
+
 	if err := req.Validate(); err != nil {
 		return nil, err
 	}
 	q := resourceQuery(req)
+	key := q.Encode()
+	if cached, ok := s.client.cache.get(ctx, key); ok {
+		return cached, nil
+	}
 
 	out := new(Resource)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/resources", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/resources", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
+	s.client.cache.set(key, req.Path, out)
 	return out, nil
 }
 
-func (s *ResourcesService) ListAllFiles(ctx context.Context, req FlatFilesRequest) (*FilesResourceList, error) {
+func (s *ResourcesService) ListAllFiles(ctx context.Context, req FlatFilesRequest) (_ *FilesResourceList, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.ListAllFiles")
+	defer func() { end(err) }()
+
 	q := url.Values{}
 	addCSV(q, "fields", req.Fields)
 	addInt(q, "limit", req.Limit)
@@ -36,14 +47,17 @@ func (s *ResourcesService) ListAllFiles(ctx context.Context, req FlatFilesReques
 	addString(q, "sort", req.Sort)
 
 	out := new(FilesResourceList)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/resources/files", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/resources/files", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *ResourcesService) ListRecentUploaded(ctx context.Context, req RecentUploadedRequest) (*LastUploadedResourceList, error) {
+func (s *ResourcesService) ListRecentUploaded(ctx context.Context, req RecentUploadedRequest) (_ *LastUploadedResourceList, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.ListRecentUploaded")
+	defer func() { end(err) }()
+
 	q := url.Values{}
 	addCSV(q, "fields", req.Fields)
 	addInt(q, "limit", req.Limit)
@@ -52,14 +66,17 @@ func (s *ResourcesService) ListRecentUploaded(ctx context.Context, req RecentUpl
 	addString(q, "preview_size", req.PreviewSize)
 
 	out := new(LastUploadedResourceList)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/resources/last-uploaded", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/resources/last-uploaded", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *ResourcesService) ListPublished(ctx context.Context, req RecentPublicRequest) (*PublicResourcesList, error) {
+func (s *ResourcesService) ListPublished(ctx context.Context, req RecentPublicRequest) (_ *PublicResourcesList, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.ListPublished")
+	defer func() { end(err) }()
+
 	q := url.Values{}
 	addCSV(q, "fields", req.Fields)
 	addInt(q, "limit", req.Limit)
@@ -69,31 +86,42 @@ func (s *ResourcesService) ListPublished(ctx context.Context, req RecentPublicRe
 	addString(q, "type", req.ResourceType)
 
 	out := new(PublicResourcesList)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/resources/public", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/resources/public", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *ResourcesService) UpdateMeta(ctx context.Context, req ResourceUpdateRequest) (*Resource, error) {
+func (s *ResourcesService) UpdateMeta(ctx context.Context, req ResourceUpdateRequest) (_ *Resource, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.UpdateMeta")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.Path)
+
 	if req.Path == "" {
 		return nil, errors.New("path is required")
 	}
+	if err := s.client.checkPrecondition(ctx, req.Path, req.IfMatch); err != nil {
+		return nil, err
+	}
 	q := url.Values{}
 	addString(q, "path", req.Path)
 	addCSV(q, "fields", req.Fields)
 
 	out := new(Resource)
 	payload := ResourcePatch{CustomProperties: req.CustomProperties}
-	_, err := s.client.doJSON(ctx, http.MethodPatch, "/disk/resources", q, payload, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodPatch, "/disk/resources", q, payload, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *ResourcesService) CreateFolder(ctx context.Context, req CreateFolderRequest) (*Link, error) {
+func (s *ResourcesService) CreateFolder(ctx context.Context, req CreateFolderRequest) (_ *Link, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.CreateFolder")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.Path)
+
 	if req.Path == "" {
 		return nil, errors.New("path is required")
 	}
@@ -102,30 +130,51 @@ func (s *ResourcesService) CreateFolder(ctx context.Context, req CreateFolderReq
 	addCSV(q, "fields", req.Fields)
 
 	out := new(Link)
-	_, err := s.client.doJSON(ctx, http.MethodPut, "/disk/resources", q, nil, out, http.StatusCreated, http.StatusConflict)
+	_, err = s.client.doJSON(ctx, http.MethodPut, "/disk/resources", q, nil, out, http.StatusCreated, http.StatusConflict)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *ResourcesService) Copy(ctx context.Context, req CopyMoveRequest) (ActionResult, error) {
+func (s *ResourcesService) Copy(ctx context.Context, req CopyMoveRequest) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.Copy")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.Path)
+
 	return s.copyOrMove(ctx, "/disk/resources/copy", req)
 }
 
-func (s *ResourcesService) Move(ctx context.Context, req CopyMoveRequest) (ActionResult, error) {
+func (s *ResourcesService) Move(ctx context.Context, req CopyMoveRequest) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.Move")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.From, req.Path)
+
 	return s.copyOrMove(ctx, "/disk/resources/move", req)
 }
 
-func (s *ResourcesService) Delete(ctx context.Context, req DeleteResourceRequest) (ActionResult, error) {
+func (s *ResourcesService) Delete(ctx context.Context, req DeleteResourceRequest) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.Delete")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.Path)
+
 	if req.Path == "" {
 		return ActionResult{}, errors.New("path is required")
 	}
+	md5 := req.MD5
+	if req.IfMatch != nil {
+		if md5 == "" {
+			md5 = req.IfMatch.MD5
+		}
+		if err := s.client.checkPrecondition(ctx, req.Path, &Precondition{Revision: req.IfMatch.Revision}); err != nil {
+			return ActionResult{}, err
+		}
+	}
 	q := url.Values{}
 	addString(q, "path", req.Path)
 	addCSV(q, "fields", req.Fields)
 	addBool(q, "force_async", req.ForceAsync)
-	addString(q, "md5", req.MD5)
+	addString(q, "md5", md5)
 	addBool(q, "permanently", req.Permanently)
 
 	out := new(Link)
@@ -139,18 +188,64 @@ func (s *ResourcesService) Delete(ctx context.Context, req DeleteResourceRequest
 	return actionFromStatus(resp.StatusCode, out), nil
 }
 
-func (s *ResourcesService) Publish(ctx context.Context, req PublishRequest) (*Link, error) {
-	return s.publishAction(ctx, "/disk/resources/publish", req)
+func (s *ResourcesService) Publish(ctx context.Context, req PublishRequest) (_ *Link, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.Publish")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.Path)
+
+	return s.publishAction(ctx, "/disk/resources/publish", true, req)
 }
 
-func (s *ResourcesService) Unpublish(ctx context.Context, req PublishRequest) (*Link, error) {
-	return s.publishAction(ctx, "/disk/resources/unpublish", req)
+func (s *ResourcesService) Unpublish(ctx context.Context, req PublishRequest) (_ *Link, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.Unpublish")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.Path)
+
+	return s.publishAction(ctx, "/disk/resources/unpublish", false, req)
+}
+
+// GetPublicSettings returns the settings of the public link to path.
+func (s *ResourcesService) GetPublicSettings(ctx context.Context, path string) (_ *PublicSettings, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.GetPublicSettings")
+	defer func() { end(err) }()
+
+	if path == "" {
+		return nil, errors.New("path is required")
+	}
+	q := url.Values{}
+	addString(q, "path", path)
+
+	out := new(PublicSettings)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/public/resources/public-settings", q, nil, out, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdatePublicSettings changes the settings of an existing public link.
+func (s *ResourcesService) UpdatePublicSettings(ctx context.Context, path string, settings PublicSettings) (err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.UpdatePublicSettings")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(path)
+
+	if path == "" {
+		return errors.New("path is required")
+	}
+	q := url.Values{}
+	addString(q, "path", path)
+
+	_, err = s.client.doJSON(ctx, http.MethodPatch, "/disk/public/resources/public-settings", q, settings, nil, http.StatusOK, http.StatusNoContent)
+	return err
 }
 
 func (s *ResourcesService) copyOrMove(ctx context.Context, endpoint string, req CopyMoveRequest) (ActionResult, error) {
 	if req.From == "" || req.Path == "" {
 		return ActionResult{}, errors.New("from and path are required")
 	}
+	if err := s.client.checkPrecondition(ctx, req.Path, req.IfMatch); err != nil {
+		return ActionResult{}, err
+	}
 	q := url.Values{}
 	addString(q, "from", req.From)
 	addString(q, "path", req.Path)
@@ -166,7 +261,7 @@ func (s *ResourcesService) copyOrMove(ctx context.Context, endpoint string, req
 	return actionFromStatus(resp.StatusCode, out), nil
 }
 
-func (s *ResourcesService) publishAction(ctx context.Context, endpoint string, req PublishRequest) (*Link, error) {
+func (s *ResourcesService) publishAction(ctx context.Context, endpoint string, publish bool, req PublishRequest) (*Link, error) {
 	if req.Path == "" {
 		return nil, errors.New("path is required")
 	}
@@ -174,8 +269,15 @@ func (s *ResourcesService) publishAction(ctx context.Context, endpoint string, r
 	addString(q, "path", req.Path)
 	addCSV(q, "fields", req.Fields)
 
+	var payload any
+	if publish {
+		addBool(q, "allow_address_access", req.AllowAddressAccess)
+		if req.PublicSettings != nil {
+			payload = publishPayload{PublicSettings: req.PublicSettings}
+		}
+	}
 	out := new(Link)
-	_, err := s.client.doJSON(ctx, http.MethodPut, endpoint, q, nil, out, http.StatusOK, http.StatusCreated)
+	_, err := s.client.doJSON(ctx, http.MethodPut, endpoint, q, payload, out, http.StatusOK, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
@@ -11,7 +11,10 @@ type TrashService struct {
 	client *Client
 }
 
-func (s *TrashService) Empty(ctx context.Context, req TrashDeleteRequest) (ActionResult, error) {
+func (s *TrashService) Empty(ctx context.Context, req TrashDeleteRequest) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Trash.Empty")
+	defer func() { end(err) }()
+
 	q := url.Values{}
 	addCSV(q, "fields", req.Fields)
 	addBool(q, "force_async", req.ForceAsync)
@@ -28,7 +31,11 @@ func (s *TrashService) Empty(ctx context.Context, req TrashDeleteRequest) (Actio
 	return actionFromStatus(resp.StatusCode, out), nil
 }
 
-func (s *TrashService) Restore(ctx context.Context, req TrashRestoreRequest) (ActionResult, error) {
+func (s *TrashService) Restore(ctx context.Context, req TrashRestoreRequest) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Trash.Restore")
+	defer func() { end(err) }()
+	defer s.client.cache.purge()
+
 	if req.Path == "" {
 		return ActionResult{}, errors.New("path is required")
 	}
@@ -47,13 +54,16 @@ func (s *TrashService) Restore(ctx context.Context, req TrashRestoreRequest) (Ac
 	return actionFromStatus(resp.StatusCode, out), nil
 }
 
-func (s *TrashService) GetMeta(ctx context.Context, req ResourceGetRequest) (*TrashResource, error) {
+func (s *TrashService) GetMeta(ctx context.Context, req ResourceGetRequest) (_ *TrashResource, err error) {
+	ctx, end := s.client.startCall(ctx, "Trash.GetMeta")
+	defer func() { end(err) }()
+
 	if req.Path == "" {
 		return nil, errors.New("path is required")
 	}
 	q := resourceQuery(req)
 	out := new(TrashResource)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/trash/resources", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/trash/resources", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
@@ -16,24 +16,35 @@ type UploadsService struct {
 	client *Client
 }
 
-func (s *UploadsService) GetUploadURL(ctx context.Context, req UploadURLRequest) (*ResourceUploadLink, error) {
+func (s *UploadsService) GetUploadURL(ctx context.Context, req UploadURLRequest) (_ *ResourceUploadLink, err error) {
+	ctx, end := s.client.startCall(ctx, "Uploads.GetUploadURL")
+	defer func() { end(err) }()
+
 	if req.Path == "" {
 		return nil, errors.New("path is required")
 	}
+	if err := s.client.checkPrecondition(ctx, req.Path, req.IfMatch); err != nil {
+		return nil, err
+	}
 	q := url.Values{}
 	addString(q, "path", req.Path)
 	addCSV(q, "fields", req.Fields)
 	addBool(q, "overwrite", req.Overwrite)
 
 	out := new(ResourceUploadLink)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/resources/upload", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/resources/upload", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
+	s.client.cache.trackUpload(out, req.Path)
 	return out, nil
 }
 
-func (s *UploadsService) UploadExternal(ctx context.Context, req UploadExternalRequest) (*Link, error) {
+func (s *UploadsService) UploadExternal(ctx context.Context, req UploadExternalRequest) (_ *Link, err error) {
+	ctx, end := s.client.startCall(ctx, "Uploads.UploadExternal")
+	defer func() { end(err) }()
+	defer s.client.cache.invalidate(req.Path)
+
 	if req.Path == "" || req.ExternalURL == "" {
 		return nil, errors.New("path and external_url are required")
 	}
@@ -44,14 +55,17 @@ func (s *UploadsService) UploadExternal(ctx context.Context, req UploadExternalR
 	addCSV(q, "fields", req.Fields)
 
 	out := new(Link)
-	_, err := s.client.doJSON(ctx, http.MethodPost, "/disk/resources/upload", q, nil, out, http.StatusAccepted, http.StatusCreated)
+	_, err = s.client.doJSON(ctx, http.MethodPost, "/disk/resources/upload", q, nil, out, http.StatusAccepted, http.StatusCreated)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *UploadsService) GetDownloadURL(ctx context.Context, req DownloadURLRequest) (*Link, error) {
+func (s *UploadsService) GetDownloadURL(ctx context.Context, req DownloadURLRequest) (_ *Link, err error) {
+	ctx, end := s.client.startCall(ctx, "Uploads.GetDownloadURL")
+	defer func() { end(err) }()
+
 	if req.Path == "" {
 		return nil, errors.New("path is required")
 	}
@@ -60,18 +74,25 @@ func (s *UploadsService) GetDownloadURL(ctx context.Context, req DownloadURLRequ
 	addCSV(q, "fields", req.Fields)
 
 	out := new(Link)
-	_, err := s.client.doJSON(ctx, http.MethodGet, "/disk/resources/download", q, nil, out, http.StatusOK)
+	_, err = s.client.doJSON(ctx, http.MethodGet, "/disk/resources/download", q, nil, out, http.StatusOK)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (s *UploadsService) OpenDownload(ctx context.Context, req DownloadURLRequest) (io.ReadCloser, error) {
+func (s *UploadsService) OpenDownload(ctx context.Context, req DownloadURLRequest, opts ...TransferOption) (_ io.ReadCloser, err error) {
+	ctx, end := s.client.startCall(ctx, "Uploads.OpenDownload")
+	defer func() { end(err) }()
+
 	link, err := s.GetDownloadURL(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	return s.client.openHref(ctx, link, opts)
+}
+
+func (c *Client) openHref(ctx context.Context, link *Link, opts []TransferOption) (io.ReadCloser, error) {
 	if link.Href == "" {
 		return nil, errors.New("empty download href")
 	}
@@ -80,22 +101,33 @@ func (s *UploadsService) OpenDownload(ctx context.Context, req DownloadURLReques
 	if err != nil {
 		return nil, err
 	}
-	resp, err := s.client.doRaw(ctx, httpReq)
+	resp, err := c.doRaw(ctx, httpReq)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		apiErr := s.client.apiErrorFromResponse(resp, body)
+		apiErr := c.apiErrorFromResponse(resp, body)
 		if err := resp.Body.Close(); err != nil {
 			return nil, errors.Join(apiErr, err)
 		}
 		return nil, apiErr
 	}
-	return resp.Body, nil
+
+	o := newTransferOptions(opts)
+	body := io.ReadCloser(&readCloser{Reader: c.throttle(ctx, TransferDownload, resp.Body, o), Closer: resp.Body})
+	tracker := c.newProgressTracker(TransferDownload, resp.ContentLength, o)
+	if tracker == nil {
+		return body, nil
+	}
+	return &progressBody{ReadCloser: body, tracker: tracker}, nil
 }
 
-func (s *UploadsService) UploadByLink(ctx context.Context, link *ResourceUploadLink, reader io.Reader) (ActionResult, error) {
+func (s *UploadsService) UploadByLink(ctx context.Context, link *ResourceUploadLink, reader io.Reader, opts ...TransferOption) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Uploads.UploadByLink")
+	defer func() { end(err) }()
+	defer s.client.cache.uploaded(link)
+
 	if link == nil || link.Href == "" || link.Method == "" {
 		return ActionResult{}, errors.New("upload link must have href and method")
 	}
@@ -103,37 +135,49 @@ func (s *UploadsService) UploadByLink(ctx context.Context, link *ResourceUploadL
 		return ActionResult{}, errors.New("reader must not be nil")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, link.Method, link.Href, reader)
-	if err != nil {
+	o := newTransferOptions(opts)
+	size := o.size
+	if size < 0 {
+		size = readerSize(reader)
+	}
+	if err := s.client.quota.check(ctx, size); err != nil {
 		return ActionResult{}, err
 	}
-	req.Header.Set("Content-Type", "application/octet-stream")
+	tracker := s.client.newProgressTracker(TransferUpload, size, o)
+	body := s.client.throttle(ctx, TransferUpload, reader, o)
+	if tracker != nil {
+		body = &progressReader{r: body, tracker: tracker}
+	}
 
-	resp, err := s.client.doRaw(ctx, req)
+	req, err := http.NewRequestWithContext(ctx, link.Method, link.Href, body)
 	if err != nil {
+		tracker.finish(err)
 		return ActionResult{}, err
 	}
-
-	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
-		body, _ := io.ReadAll(resp.Body)
-		apiErr := s.client.apiErrorFromResponse(resp, body)
-		if err := resp.Body.Close(); err != nil {
-			return ActionResult{}, errors.Join(apiErr, err)
-		}
-		return ActionResult{}, apiErr
+	if size > 0 {
+		req.ContentLength = size
 	}
-	if err := resp.Body.Close(); err != nil {
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	status, err := s.sendUploadPart(ctx, req)
+	tracker.finish(err)
+	if err != nil {
 		return ActionResult{}, err
 	}
+	s.client.quota.uploaded(size)
 
-	result := ActionResult{StatusCode: resp.StatusCode}
-	if resp.StatusCode == http.StatusAccepted {
+	result := ActionResult{StatusCode: status}
+	if status == http.StatusAccepted {
 		result.Operation = &OperationRef{ID: link.OperationID, Href: link.Href}
 	}
 	return result, nil
 }
 
-func (s *UploadsService) UploadInChunks(ctx context.Context, link *ResourceUploadLink, reader io.ReadSeeker, cfg UploadChunkRequest) (ActionResult, error) {
+func (s *UploadsService) UploadInChunks(ctx context.Context, link *ResourceUploadLink, reader io.ReadSeeker, cfg UploadChunkRequest, opts ...TransferOption) (_ ActionResult, err error) {
+	ctx, end := s.client.startCall(ctx, "Uploads.UploadInChunks")
+	defer func() { end(err) }()
+	defer s.client.cache.uploaded(link)
+
 	if link == nil || link.Href == "" || link.Method == "" {
 		return ActionResult{}, errors.New("upload link must have href and method")
 	}
@@ -155,13 +199,28 @@ func (s *UploadsService) UploadInChunks(ctx context.Context, link *ResourceUploa
 	if _, err := reader.Seek(0, io.SeekStart); err != nil {
 		return ActionResult{}, err
 	}
+	if err := s.client.quota.check(ctx, total); err != nil {
+		return ActionResult{}, err
+	}
 
+	o := newTransferOptions(opts)
+	tracker := s.client.newProgressTracker(TransferUpload, total, o)
+	err = s.uploadChunks(ctx, link, reader, partSize, total, o, tracker)
+	tracker.finish(err)
+	if err != nil {
+		return ActionResult{}, err
+	}
+	s.client.quota.uploaded(total)
+	return ActionResult{StatusCode: http.StatusAccepted, Operation: &OperationRef{ID: link.OperationID, Href: link.Href}}, nil
+}
+
+func (s *UploadsService) uploadChunks(ctx context.Context, link *ResourceUploadLink, reader io.Reader, partSize, total int64, o transferOptions, tracker *progressTracker) error {
 	buf := make([]byte, partSize)
 	var start int64
 	for start < total {
 		select {
 		case <-ctx.Done():
-			return ActionResult{}, ctx.Err()
+			return ctx.Err()
 		default:
 		}
 
@@ -173,39 +232,58 @@ func (s *UploadsService) UploadInChunks(ctx context.Context, link *ResourceUploa
 
 		n, err := io.ReadFull(reader, buf[:chunkSize])
 		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
-			return ActionResult{}, err
+			return err
 		}
 		if n == 0 {
 			break
 		}
 
-		end := start + int64(n) - 1
-		req, err := http.NewRequestWithContext(ctx, link.Method, link.Href, io.NopCloser(bytesReader(buf[:n])))
-		if err != nil {
-			return ActionResult{}, err
-		}
-		req.Header.Set("Content-Type", "application/octet-stream")
-		req.Header.Set("Content-Range", "bytes "+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(end, 10)+"/"+strconv.FormatInt(total, 10))
-
-		resp, err := s.client.doRaw(ctx, req)
-		if err != nil {
-			return ActionResult{}, err
+		body := s.client.throttle(ctx, TransferUpload, bytesReader(buf[:n]), o)
+		if tracker != nil {
+			body = &progressReader{r: body, tracker: tracker}
 		}
-		if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
-			body, _ := io.ReadAll(resp.Body)
-			apiErr := s.client.apiErrorFromResponse(resp, body)
-			if err := resp.Body.Close(); err != nil {
-				return ActionResult{}, errors.Join(apiErr, err)
-			}
-			return ActionResult{}, apiErr
-		}
-		if err := resp.Body.Close(); err != nil {
-			return ActionResult{}, err
+		if err := s.sendChunk(ctx, link, body, start, int64(n), total); err != nil {
+			return err
 		}
+		tracker.completeChunk()
 		start += int64(n)
 	}
+	return nil
+}
 
-	return ActionResult{StatusCode: http.StatusAccepted, Operation: &OperationRef{ID: link.OperationID, Href: link.Href}}, nil
+func (s *UploadsService) sendChunk(ctx context.Context, link *ResourceUploadLink, body io.Reader, start, n, total int64) (err error) {
+	ctx, end := s.client.startCall(ctx, "Uploads.UploadChunk")
+	defer func() { end(err) }()
+
+	req, err := http.NewRequestWithContext(ctx, link.Method, link.Href, io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = n
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Range", "bytes "+strconv.FormatInt(start, 10)+"-"+strconv.FormatInt(start+n-1, 10)+"/"+strconv.FormatInt(total, 10))
+
+	_, err = s.sendUploadPart(ctx, req)
+	return err
+}
+
+func (s *UploadsService) sendUploadPart(ctx context.Context, req *http.Request) (int, error) {
+	resp, err := s.client.doRaw(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		apiErr := s.client.apiErrorFromResponse(resp, body)
+		if err := resp.Body.Close(); err != nil {
+			return 0, errors.Join(apiErr, err)
+		}
+		return 0, apiErr
+	}
+	if err := resp.Body.Close(); err != nil {
+		return 0, err
+	}
+	return resp.StatusCode, nil
 }
 
 func bytesReader(p []byte) io.Reader {
@@ -0,0 +1,150 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func TestSnapshotFormatsAndDiff(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	mod := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv.PutFile("disk:/docs/a.txt", []byte("a"), mod)
+	srv.PutFile("disk:/docs/sub/b.txt", []byte("bb"), mod)
+	srv.PutFile("disk:/other/c.txt", []byte("c"), mod)
+	if _, err := client.Resources.Publish(ctx, PublishRequest{Path: "disk:/docs/a.txt"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		new  func(*bytes.Buffer) SnapshotWriter
+	}{
+		{"jsonl", func(b *bytes.Buffer) SnapshotWriter { return NewJSONLSnapshotWriter(b) }},
+		{"csv", func(b *bytes.Buffer) SnapshotWriter { return NewCSVSnapshotWriter(b) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, err := client.Resources.Snapshot(ctx, SnapshotRequest{Root: "disk:/docs", IncludeFolders: true}, tc.new(&buf))
+			if err != nil {
+				t.Fatalf("snapshot: %v", err)
+			}
+			entries, err := ReadSnapshot(&buf)
+			if err != nil {
+				t.Fatalf("read snapshot: %v", err)
+			}
+			if n != 3 || len(entries) != 3 {
+				t.Fatalf("n = %d entries = %+v", n, entries)
+			}
+			byPath := map[string]SnapshotEntry{}
+			for _, e := range entries {
+				byPath[e.Path] = e
+			}
+			if a := byPath["disk:/docs/a.txt"]; a.PublicURL == "" || a.MD5 == "" || !a.Modified.Equal(mod) {
+				t.Fatalf("a.txt = %+v", a)
+			}
+			if byPath["disk:/docs/sub"].Type != "dir" {
+				t.Fatalf("folder entry missing: %+v", entries)
+			}
+		})
+	}
+
+	var before bytes.Buffer
+	if _, err := client.Resources.Snapshot(ctx, SnapshotRequest{}, NewJSONLSnapshotWriter(&before)); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	srv.PutFile("disk:/docs/a.txt", []byte("changed"), mod)
+	srv.Remove("disk:/other/c.txt")
+	srv.PutFile("disk:/new.txt", []byte("n"), mod)
+	var after bytes.Buffer
+	if _, err := client.Resources.Snapshot(ctx, SnapshotRequest{}, NewCSVSnapshotWriter(&after)); err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+
+	a, err := ReadSnapshot(&before)
+	if err != nil {
+		t.Fatalf("read before: %v", err)
+	}
+	b, err := ReadSnapshot(&after)
+	if err != nil {
+		t.Fatalf("read after: %v", err)
+	}
+	if d := DiffSnapshots(a, a); !d.Empty() {
+		t.Fatalf("self diff = %+v", d)
+	}
+	d := DiffSnapshots(a, b)
+	if len(d.Added) != 1 || d.Added[0].Path != "disk:/new.txt" {
+		t.Fatalf("added = %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Path != "disk:/other/c.txt" {
+		t.Fatalf("removed = %+v", d.Removed)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].New.Size != 7 || d.Changed[0].Old.Size != 1 {
+		t.Fatalf("changed = %+v", d.Changed)
+	}
+}
+
+func TestFileIteratorPages(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	for _, p := range []string{"a", "b", "c", "d", "e"} {
+		srv.PutFile("disk:/"+p+".txt", []byte(p), time.Now())
+	}
+	limit := 2
+	it := client.Resources.IterateFiles(context.Background(), FlatFilesRequest{Limit: &limit, Sort: "path"})
+	var got []string
+	for it.Next() {
+		got = append(got, it.Resource().Name)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iterate: %v", err)
+	}
+	if len(got) != 5 || got[0] != "a.txt" || got[4] != "e.txt" {
+		t.Fatalf("files = %v", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it = client.Resources.IterateFiles(ctx, FlatFilesRequest{Limit: &limit})
+	it.Next()
+	it.Next()
+	cancel()
+	if it.Next() || it.Err() == nil {
+		t.Fatal("iterator continued after its context was cancelled")
+	}
+}
+
+func TestSnapshotAppFolderRoot(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile(fakedisk.AppFolder+"/x/a.txt", []byte("a"), time.Now())
+	srv.PutFile(fakedisk.AppFolder+"/b.txt", []byte("b"), time.Now())
+	srv.PutFile("disk:/x/c.txt", []byte("c"), time.Now())
+	srv.PutFile("disk:/d.txt", []byte("d"), time.Now())
+
+	for root, want := range map[string][]string{
+		"app:/":  {"disk:" + fakedisk.AppFolder + "/b.txt", "disk:" + fakedisk.AppFolder + "/x/a.txt"},
+		"app:/x": {"disk:" + fakedisk.AppFolder + "/x/a.txt"},
+	} {
+		var buf bytes.Buffer
+		if _, err := client.Resources.Snapshot(ctx, SnapshotRequest{Root: root}, NewJSONLSnapshotWriter(&buf)); err != nil {
+			t.Fatalf("snapshot %s: %v", root, err)
+		}
+		entries, err := ReadSnapshot(&buf)
+		if err != nil {
+			t.Fatalf("read snapshot: %v", err)
+		}
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Path)
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("snapshot %s = %v want %v", root, got, want)
+		}
+	}
+}
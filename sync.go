@@ -0,0 +1,624 @@
+package yadisk
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSyncStateFile  = ".yadisk-sync.json"
+	defaultSyncTrashDir   = ".yadisk-trash"
+	defaultConflictSuffix = " (conflict)"
+	syncStateVersion      = 1
+)
+
+var ErrSyncConflict = errors.New("sync conflict")
+
+type ConflictPolicy int
+
+const (
+	ConflictNewerWins ConflictPolicy = iota
+	ConflictKeepBoth
+	ConflictFail
+)
+
+type SyncAction string
+
+const (
+	SyncUpload       SyncAction = "upload"
+	SyncDownload     SyncAction = "download"
+	SyncDeleteRemote SyncAction = "delete-remote"
+	SyncDeleteLocal  SyncAction = "delete-local"
+	SyncKeepBoth     SyncAction = "keep-both"
+	SyncConflict     SyncAction = "conflict"
+)
+
+type SyncRequest struct {
+	LocalDir  string
+	RemoteDir string
+	// StatePath defaults to LocalDir/.yadisk-sync.json.
+	StatePath string
+	Conflict  ConflictPolicy
+	// ConflictSuffix is inserted before the extension of the copy kept by
+	// ConflictKeepBoth. Defaults to " (conflict)".
+	ConflictSuffix string
+	// LocalTrashDir receives local files deleted because they were removed
+	// remotely. Defaults to LocalDir/.yadisk-trash.
+	LocalTrashDir string
+	DryRun        bool
+}
+
+type SyncItem struct {
+	Path   string
+	Action SyncAction
+	Err    error
+}
+
+type SyncReport struct {
+	Items []SyncItem
+}
+
+func (r *SyncReport) Failed() []SyncItem {
+	var out []SyncItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+type SyncService struct {
+	client *Client
+}
+
+type syncState struct {
+	Version int                  `json:"version"`
+	Entries map[string]syncEntry `json:"entries"`
+}
+
+type syncEntry struct {
+	MD5      string    `json:"md5"`
+	Revision int64     `json:"revision"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+}
+
+type localFile struct {
+	abs     string
+	size    int64
+	modTime time.Time
+	md5     string
+}
+
+type syncRun struct {
+	client     *Client
+	req        SyncRequest
+	remoteRoot string
+	state      *syncState
+	madeDirs   map[string]bool
+	report     *SyncReport
+}
+
+// Bidirectional reconciles LocalDir and RemoteDir using the state recorded by
+// the previous run. Edits on one side are copied to the other, deletions are
+// propagated to the Disk trash or the local trash directory, and edits on
+// both sides are resolved by req.Conflict. Only files are synced; directories
+// are created as needed but never removed.
+func (s *SyncService) Bidirectional(ctx context.Context, req SyncRequest) (_ *SyncReport, err error) {
+	ctx, end := s.client.startCall(ctx, "Sync.Bidirectional")
+	defer func() { end(err) }()
+
+	if req.LocalDir == "" || req.RemoteDir == "" {
+		return nil, errors.New("local and remote directories are required")
+	}
+	if req.StatePath == "" {
+		req.StatePath = filepath.Join(req.LocalDir, defaultSyncStateFile)
+	}
+	if req.LocalTrashDir == "" {
+		req.LocalTrashDir = filepath.Join(req.LocalDir, defaultSyncTrashDir)
+	}
+	if req.ConflictSuffix == "" {
+		req.ConflictSuffix = defaultConflictSuffix
+	}
+
+	state, err := loadSyncState(req.StatePath)
+	if err != nil {
+		return nil, err
+	}
+	run := &syncRun{
+		client:     s.client,
+		req:        req,
+		remoteRoot: strings.TrimSuffix(req.RemoteDir, "/"),
+		state:      state,
+		madeDirs:   make(map[string]bool),
+		report:     &SyncReport{},
+	}
+
+	local, err := run.scanLocal()
+	if err != nil {
+		return nil, err
+	}
+	remote, err := run.scanRemote(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]struct{}, len(local)+len(remote)+len(state.Entries))
+	for p := range local {
+		paths[p] = struct{}{}
+	}
+	for p := range remote {
+		paths[p] = struct{}{}
+	}
+	for p := range state.Entries {
+		paths[p] = struct{}{}
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var conflicts int
+	for _, rel := range sorted {
+		if err := ctx.Err(); err != nil {
+			return run.report, err
+		}
+		item, ok := run.reconcile(ctx, rel, local[rel], remote[rel])
+		if !ok {
+			continue
+		}
+		if errors.Is(item.Err, ErrSyncConflict) {
+			conflicts++
+		}
+		run.report.Items = append(run.report.Items, item)
+	}
+
+	if !req.DryRun {
+		if err := saveSyncState(req.StatePath, state); err != nil {
+			return run.report, err
+		}
+	}
+	if conflicts > 0 {
+		return run.report, fmt.Errorf("%w: %d unresolved", ErrSyncConflict, conflicts)
+	}
+	return run.report, nil
+}
+
+func (r *syncRun) reconcile(ctx context.Context, rel string, local *localFile, remote *Resource) (SyncItem, bool) {
+	prev, known := r.state.Entries[rel]
+
+	switch {
+	case local != nil && remote != nil:
+		if local.md5 == remote.MD5 {
+			r.record(rel, local, remote)
+			return SyncItem{}, false
+		}
+		localChanged := !known || local.md5 != prev.MD5
+		remoteChanged := !known || remoteDiffers(prev, remote)
+		switch {
+		case localChanged && remoteChanged:
+			return r.resolveConflict(ctx, rel, local, remote)
+		case localChanged:
+			return r.apply(rel, SyncUpload, func() error { return r.upload(ctx, rel, local) })
+		default:
+			return r.apply(rel, SyncDownload, func() error { return r.download(ctx, rel, remote) })
+		}
+
+	case local != nil:
+		if known && local.md5 == prev.MD5 {
+			return r.apply(rel, SyncDeleteLocal, func() error { return r.deleteLocal(rel, local) })
+		}
+		return r.apply(rel, SyncUpload, func() error { return r.upload(ctx, rel, local) })
+
+	case remote != nil:
+		if known && !remoteDiffers(prev, remote) {
+			return r.apply(rel, SyncDeleteRemote, func() error { return r.deleteRemote(ctx, rel, remote) })
+		}
+		return r.apply(rel, SyncDownload, func() error { return r.download(ctx, rel, remote) })
+
+	default:
+		if !r.req.DryRun {
+			delete(r.state.Entries, rel)
+		}
+		return SyncItem{}, false
+	}
+}
+
+func (r *syncRun) resolveConflict(ctx context.Context, rel string, local *localFile, remote *Resource) (SyncItem, bool) {
+	switch r.req.Conflict {
+	case ConflictKeepBoth:
+		return r.apply(rel, SyncKeepBoth, func() error { return r.keepBoth(ctx, rel, local, remote) })
+	case ConflictFail:
+		return SyncItem{Path: rel, Action: SyncConflict, Err: ErrSyncConflict}, true
+	default:
+		if remote.Modified.Valid && remote.Modified.Time.After(local.modTime) {
+			return r.apply(rel, SyncDownload, func() error { return r.download(ctx, rel, remote) })
+		}
+		return r.apply(rel, SyncUpload, func() error { return r.upload(ctx, rel, local) })
+	}
+}
+
+func (r *syncRun) apply(rel string, action SyncAction, fn func() error) (SyncItem, bool) {
+	item := SyncItem{Path: rel, Action: action}
+	if !r.req.DryRun {
+		item.Err = fn()
+	}
+	return item, true
+}
+
+func (r *syncRun) record(rel string, local *localFile, remote *Resource) {
+	if r.req.DryRun {
+		return
+	}
+	entry := syncEntry{MD5: local.md5, Size: local.size, ModTime: local.modTime}
+	if remote != nil {
+		entry.Revision = remote.Revision
+	}
+	r.state.Entries[rel] = entry
+}
+
+func remoteDiffers(prev syncEntry, remote *Resource) bool {
+	if remote.MD5 != "" && prev.MD5 != "" {
+		return remote.MD5 != prev.MD5
+	}
+	return remote.Revision != prev.Revision
+}
+
+func (r *syncRun) scanLocal() (map[string]*localFile, error) {
+	out := make(map[string]*localFile)
+	stateAbs, _ := filepath.Abs(r.req.StatePath)
+	trashAbs, _ := filepath.Abs(r.req.LocalTrashDir)
+
+	err := filepath.WalkDir(r.req.LocalDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		abs, _ := filepath.Abs(p)
+		if d.IsDir() {
+			if abs == trashAbs {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if abs == stateAbs || !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".yadisk-part-") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(r.req.LocalDir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		file := &localFile{abs: p, size: info.Size(), modTime: info.ModTime()}
+		if prev, ok := r.state.Entries[rel]; ok && prev.Size == file.size && prev.ModTime.Equal(file.modTime) {
+			file.md5 = prev.MD5
+		} else if file.md5, err = fileMD5(p); err != nil {
+			return err
+		}
+		out[rel] = file
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return out, nil
+	}
+	return out, err
+}
+
+func (r *syncRun) scanRemote(ctx context.Context) (map[string]*Resource, error) {
+	out := make(map[string]*Resource)
+	listRoot, err := r.client.resolveRemotePath(ctx, r.remoteRoot)
+	if err != nil {
+		return nil, err
+	}
+	err = r.client.Resources.Walk(ctx, r.remoteRoot, func(res Resource) error {
+		if res.Type == "dir" {
+			return nil
+		}
+		rel, ok := relativeRemotePath(listRoot, res.Path)
+		if !ok {
+			return nil
+		}
+		item := res
+		out[rel] = &item
+		return nil
+	})
+	if isNotFound(err) {
+		return out, nil
+	}
+	if err == nil {
+		r.madeDirs[r.remoteRoot] = true
+	}
+	return out, err
+}
+
+func (r *syncRun) remotePath(rel string) string {
+	return r.remoteRoot + "/" + rel
+}
+
+func (r *syncRun) upload(ctx context.Context, rel string, local *localFile) error {
+	remotePath := r.remotePath(rel)
+	if err := r.ensureRemoteDir(ctx, path.Dir(remotePath)); err != nil {
+		return err
+	}
+
+	f, err := os.Open(local.abs)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	overwrite := true
+	link, err := r.client.Uploads.GetUploadURL(ctx, UploadURLRequest{Path: remotePath, Overwrite: &overwrite})
+	if err != nil {
+		return err
+	}
+	if _, err := r.client.Uploads.UploadByLink(ctx, link, f); err != nil {
+		return err
+	}
+
+	remote, err := r.client.Resources.GetMeta(ctx, ResourceGetRequest{Path: remotePath, Fields: []string{"md5", "revision"}})
+	if err != nil {
+		remote = nil
+	}
+	r.record(rel, local, remote)
+	return nil
+}
+
+func (r *syncRun) download(ctx context.Context, rel string, remote *Resource) error {
+	dest := filepath.Join(r.req.LocalDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+
+	body, err := r.client.Uploads.OpenDownload(ctx, DownloadURLRequest{Path: remote.Path})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = body.Close() }()
+
+	sum, err := writeDownload(dest, body, remote.Path, remote.MD5, remote.Modified)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		return err
+	}
+	r.record(rel, &localFile{abs: dest, size: info.Size(), modTime: info.ModTime(), md5: sum}, remote)
+	return nil
+}
+
+func (r *syncRun) deleteRemote(ctx context.Context, rel string, remote *Resource) error {
+	_, err := r.client.Resources.Delete(ctx, DeleteResourceRequest{Path: remote.Path, MD5: remote.MD5})
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	delete(r.state.Entries, rel)
+	return nil
+}
+
+func (r *syncRun) deleteLocal(rel string, local *localFile) error {
+	dest := filepath.Join(r.req.LocalTrashDir, filepath.FromSlash(rel))
+	if _, err := os.Stat(dest); err == nil {
+		dest += "." + time.Now().UTC().Format("20060102T150405")
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(local.abs, dest); err != nil {
+		return err
+	}
+	delete(r.state.Entries, rel)
+	return nil
+}
+
+func (r *syncRun) keepBoth(ctx context.Context, rel string, local *localFile, remote *Resource) error {
+	conflictRel, err := r.freeConflictName(rel)
+	if err != nil {
+		return err
+	}
+	conflictAbs := filepath.Join(r.req.LocalDir, filepath.FromSlash(conflictRel))
+	if err := os.Rename(local.abs, conflictAbs); err != nil {
+		return err
+	}
+	moved := *local
+	moved.abs = conflictAbs
+	if err := r.upload(ctx, conflictRel, &moved); err != nil {
+		return err
+	}
+	return r.download(ctx, rel, remote)
+}
+
+func (r *syncRun) ensureRemoteDir(ctx context.Context, dir string) error {
+	if r.madeDirs[dir] {
+		return nil
+	}
+	if err := r.client.Resources.MkdirAll(ctx, dir); err != nil {
+		return err
+	}
+	for d := dir; !r.madeDirs[d] && strings.HasPrefix(d, r.remoteRoot); d = path.Dir(d) {
+		r.madeDirs[d] = true
+	}
+	return nil
+}
+
+// freeConflictName returns the first conflict name for rel that is neither
+// a local file nor tracked from an earlier run, so an older conflict copy is
+// never replaced.
+func (r *syncRun) freeConflictName(rel string) (string, error) {
+	for n := 1; ; n++ {
+		suffix := r.req.ConflictSuffix
+		if n > 1 {
+			// " (conflict)" becomes " (conflict 2)".
+			if base, ok := strings.CutSuffix(suffix, ")"); ok {
+				suffix = fmt.Sprintf("%s %d)", base, n)
+			} else {
+				suffix = fmt.Sprintf("%s %d", suffix, n)
+			}
+		}
+		name := conflictName(rel, suffix)
+		if _, tracked := r.state.Entries[name]; tracked {
+			continue
+		}
+		_, err := os.Lstat(filepath.Join(r.req.LocalDir, filepath.FromSlash(name)))
+		if errors.Is(err, fs.ErrNotExist) {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+func conflictName(rel, suffix string) string {
+	ext := path.Ext(rel)
+	return strings.TrimSuffix(rel, ext) + suffix + ext
+}
+
+// relativeRemotePath returns p relative to root, reporting false unless p is
+// strictly below root in the same scheme. Paths without a scheme are disk
+// paths. An "app:" root does not contain the "disk:" paths the API returns
+// for it; resolve it with resolveRemotePath first.
+func relativeRemotePath(root, p string) (string, bool) {
+	rootPath, err := ParsePath(root)
+	if err != nil {
+		return "", false
+	}
+	target, err := ParsePath(p)
+	if err != nil {
+		return "", false
+	}
+	rel, err := rootPath.Rel(target)
+	if err != nil || rel == "." {
+		return "", false
+	}
+	return rel, true
+}
+
+// resolveRemotePath returns p as the API reports it, which is the "disk:"
+// form for "app:" paths. A missing p is returned unchanged.
+func (c *Client) resolveRemotePath(ctx context.Context, p string) (string, error) {
+	r, err := c.Resources.GetMeta(ctx, ResourceGetRequest{Path: p, Fields: []string{"path"}})
+	if isNotFound(err) {
+		return p, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return r.Path, nil
+}
+
+// stripDiskScheme returns the slash-rooted path of p without its scheme.
+// "disk:/a", "app:/a" and "trash:/a" all become "/a", so only compare
+// results of paths known to share a scheme.
+func stripDiskScheme(p string) string {
+	for _, sc := range []PathScheme{SchemeDisk, SchemeApp, SchemeTrash} {
+		if rest, ok := strings.CutPrefix(p, string(sc)+":"); ok {
+			p = rest
+			break
+		}
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// writeDownload stores body at dest through a temporary file in the same
+// folder, so dest is replaced only by a complete file. The content must match
+// wantMD5 when it is set; name identifies the remote file in that error. The
+// file gets modified as its modification time when valid. It returns the MD5
+// of the content.
+func writeDownload(dest string, body io.Reader, name, wantMD5 string, modified Timestamp) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".yadisk-part-*")
+	if err != nil {
+		return "", err
+	}
+	hash := md5.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+	if err == nil && wantMD5 != "" && sum != wantMD5 {
+		err = fmt.Errorf("md5 mismatch for %s", name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if modified.Valid {
+		_ = os.Chtimes(tmp.Name(), modified.Time, modified.Time)
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return sum, nil
+}
+
+func fileMD5(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func loadSyncState(name string) (*syncState, error) {
+	state := &syncState{Version: syncStateVersion, Entries: make(map[string]syncEntry)}
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode sync state %s: %w", name, err)
+	}
+	if state.Entries == nil {
+		state.Entries = make(map[string]syncEntry)
+	}
+	return state, nil
+}
+
+func saveSyncState(name string, state *syncState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,223 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func newFakeDiskClient(t *testing.T) (*Client, *fakedisk.Server) {
+	t.Helper()
+	srv := fakedisk.New()
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(WithOAuthToken("token"), WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client, srv
+}
+
+func writeLocal(t *testing.T, dir, rel, data string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func readLocal(t *testing.T, dir, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("read %s: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestSyncBidirectionalPropagatesEditsAndDeletes(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	local := t.TempDir()
+	ctx := context.Background()
+	req := SyncRequest{LocalDir: local, RemoteDir: "disk:/team"}
+
+	writeLocal(t, local, "docs/a.txt", "local a")
+	srv.PutFile("disk:/team/b.txt", []byte("remote b"), time.Now())
+	srv.PutFile("disk:/team/c.txt", []byte("remote c"), time.Now())
+
+	if _, err := client.Sync.Bidirectional(ctx, req); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if got, _ := srv.File("disk:/team/docs/a.txt"); string(got) != "local a" {
+		t.Fatalf("remote a = %q", got)
+	}
+	if got := readLocal(t, local, "b.txt"); got != "remote b" {
+		t.Fatalf("local b = %q", got)
+	}
+
+	if err := os.Remove(filepath.Join(local, "b.txt")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	srv.Remove("disk:/team/c.txt")
+	srv.PutFile("disk:/team/docs/a.txt", []byte("remote edit"), time.Now())
+
+	report, err := client.Sync.Bidirectional(ctx, req)
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if len(report.Items) != 3 {
+		t.Fatalf("items = %+v", report.Items)
+	}
+	if srv.Exists("disk:/team/b.txt") {
+		t.Fatal("remote b should be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(local, "c.txt")); !os.IsNotExist(err) {
+		t.Fatalf("local c should be moved to trash: %v", err)
+	}
+	if got := readLocal(t, local, ".yadisk-trash/c.txt"); got != "remote c" {
+		t.Fatalf("trashed c = %q", got)
+	}
+	if got := readLocal(t, local, "docs/a.txt"); got != "remote edit" {
+		t.Fatalf("local a = %q", got)
+	}
+
+	report, err = client.Sync.Bidirectional(ctx, req)
+	if err != nil {
+		t.Fatalf("third sync: %v", err)
+	}
+	if len(report.Items) != 0 {
+		t.Fatalf("expected no-op sync, got %+v", report.Items)
+	}
+}
+
+func TestSyncConflictPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, policy ConflictPolicy) (*Client, *fakedisk.Server, SyncRequest) {
+		client, srv := newFakeDiskClient(t)
+		local := t.TempDir()
+		req := SyncRequest{LocalDir: local, RemoteDir: "disk:/shared", Conflict: policy}
+		writeLocal(t, local, "notes.txt", "base")
+		if _, err := client.Sync.Bidirectional(ctx, req); err != nil {
+			t.Fatalf("initial sync: %v", err)
+		}
+		writeLocal(t, local, "notes.txt", "local edit")
+		srv.PutFile("disk:/shared/notes.txt", []byte("remote edit"), time.Now().Add(time.Hour))
+		return client, srv, req
+	}
+
+	t.Run("keep-both", func(t *testing.T) {
+		client, srv, req := setup(t, ConflictKeepBoth)
+		if _, err := client.Sync.Bidirectional(ctx, req); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+		if got := readLocal(t, req.LocalDir, "notes.txt"); got != "remote edit" {
+			t.Fatalf("local = %q", got)
+		}
+		if got, _ := srv.File("disk:/shared/notes (conflict).txt"); string(got) != "local edit" {
+			t.Fatalf("conflict copy = %q", got)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		client, srv, req := setup(t, ConflictFail)
+		report, err := client.Sync.Bidirectional(ctx, req)
+		if !errors.Is(err, ErrSyncConflict) {
+			t.Fatalf("err = %v", err)
+		}
+		if len(report.Failed()) != 1 {
+			t.Fatalf("failed = %+v", report.Failed())
+		}
+		if got, _ := srv.File("disk:/shared/notes.txt"); string(got) != "remote edit" {
+			t.Fatalf("remote = %q", got)
+		}
+		if got := readLocal(t, req.LocalDir, "notes.txt"); got != "local edit" {
+			t.Fatalf("local = %q", got)
+		}
+	})
+
+	t.Run("newer-wins", func(t *testing.T) {
+		client, _, req := setup(t, ConflictNewerWins)
+		if _, err := client.Sync.Bidirectional(ctx, req); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+		if got := readLocal(t, req.LocalDir, "notes.txt"); got != "remote edit" {
+			t.Fatalf("local = %q", got)
+		}
+	})
+}
+
+func TestSyncDryRunLeavesState(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	local := t.TempDir()
+	writeLocal(t, local, "a.txt", "a")
+
+	report, err := client.Sync.Bidirectional(context.Background(), SyncRequest{LocalDir: local, RemoteDir: "disk:/x", DryRun: true})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(report.Items) != 1 || report.Items[0].Action != SyncUpload {
+		t.Fatalf("items = %+v", report.Items)
+	}
+	if srv.Exists("disk:/x/a.txt") {
+		t.Fatal("dry run uploaded")
+	}
+	if _, err := os.Stat(filepath.Join(local, defaultSyncStateFile)); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote state: %v", err)
+	}
+}
+
+func TestSyncKeepBothKeepsEarlierConflictCopies(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	local := t.TempDir()
+	ctx := context.Background()
+	req := SyncRequest{LocalDir: local, RemoteDir: "disk:/shared", Conflict: ConflictKeepBoth}
+	writeLocal(t, local, "notes.txt", "base")
+	if _, err := client.Sync.Bidirectional(ctx, req); err != nil {
+		t.Fatalf("initial sync: %v", err)
+	}
+	for i, edit := range []string{"first", "second"} {
+		writeLocal(t, local, "notes.txt", "local "+edit)
+		srv.PutFile("disk:/shared/notes.txt", []byte("remote "+edit), time.Now().Add(time.Duration(i+1)*time.Hour))
+		if _, err := client.Sync.Bidirectional(ctx, req); err != nil {
+			t.Fatalf("sync %s: %v", edit, err)
+		}
+	}
+	if got := readLocal(t, local, "notes (conflict).txt"); got != "local first" {
+		t.Fatalf("first conflict copy = %q", got)
+	}
+	if got := readLocal(t, local, "notes (conflict 2).txt"); got != "local second" {
+		t.Fatalf("second conflict copy = %q", got)
+	}
+}
+
+func TestSyncAppFolderRoot(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	local := t.TempDir()
+	ctx := context.Background()
+	req := SyncRequest{LocalDir: local, RemoteDir: "app:/backup"}
+	srv.PutFile(fakedisk.AppFolder+"/backup/a.txt", []byte("remote a"), time.Now())
+	writeLocal(t, local, "b.txt", "local b")
+
+	if _, err := client.Sync.Bidirectional(ctx, req); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got := readLocal(t, local, "a.txt"); got != "remote a" {
+		t.Fatalf("local a = %q", got)
+	}
+	if got, _ := srv.File(fakedisk.AppFolder + "/backup/b.txt"); string(got) != "local b" {
+		t.Fatalf("remote b = %q", got)
+	}
+	report, err := client.Sync.Bidirectional(ctx, req)
+	if err != nil || len(report.Items) != 0 {
+		t.Fatalf("second sync = %+v, %v", report, err)
+	}
+}
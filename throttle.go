@@ -0,0 +1,164 @@
+package yadisk
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+const throttleReadSize = 16 * 1024
+
+// tokenBucket hands out n tokens at rate per second with a burst of one
+// second's worth. Callers may overdraw it; reserve returns how long to wait
+// before the debt is paid back. A non-positive rate means unlimited.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64) *tokenBucket {
+	return &tokenBucket{rate: rate, tokens: burstFor(rate), last: time.Now()}
+}
+
+func burstFor(rate float64) float64 {
+	if rate < 1 {
+		return 1
+	}
+	return rate
+}
+
+func (b *tokenBucket) setRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refillLocked(time.Now())
+	burst := burstFor(rate)
+	if b.rate <= 0 || b.tokens > burst {
+		b.tokens = burst
+	}
+	b.rate = rate
+}
+
+func (b *tokenBucket) currentRate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rate
+}
+
+func (b *tokenBucket) reserve(n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.rate <= 0 {
+		return 0
+	}
+	b.refillLocked(time.Now())
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) refillLocked(now time.Time) {
+	if b.rate > 0 {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if burst := burstFor(b.rate); b.tokens > burst {
+			b.tokens = burst
+		}
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) wait(ctx context.Context, n float64) error {
+	if d := b.reserve(n); d > 0 {
+		return sleepWithContext(ctx, d)
+	}
+	return ctx.Err()
+}
+
+// BandwidthLimiter caps throughput in bytes per second. It is safe for
+// concurrent use, may be shared between transfers and can be adjusted while
+// transfers are running. A zero limit disables throttling.
+type BandwidthLimiter struct {
+	bucket *tokenBucket
+}
+
+func NewBandwidthLimiter(bytesPerSec int64) *BandwidthLimiter {
+	return &BandwidthLimiter{bucket: newTokenBucket(float64(bytesPerSec))}
+}
+
+func (l *BandwidthLimiter) SetLimit(bytesPerSec int64) {
+	l.bucket.setRate(float64(bytesPerSec))
+}
+
+func (l *BandwidthLimiter) Limit() int64 {
+	return int64(l.bucket.currentRate())
+}
+
+// WaitN blocks until n bytes may pass or ctx is done.
+func (l *BandwidthLimiter) WaitN(ctx context.Context, n int) error {
+	return l.bucket.wait(ctx, float64(n))
+}
+
+// WithRateLimit caps a single transfer at bytesPerSec in addition to any
+// client-wide limit.
+func WithRateLimit(bytesPerSec int64) TransferOption {
+	return func(o *transferOptions) {
+		o.limiters = append(o.limiters, NewBandwidthLimiter(bytesPerSec))
+	}
+}
+
+// WithLimiter throttles a transfer with a caller-owned limiter, so several
+// transfers can share one budget that is adjusted at runtime.
+func WithLimiter(l *BandwidthLimiter) TransferOption {
+	return func(o *transferOptions) {
+		if l != nil {
+			o.limiters = append(o.limiters, l)
+		}
+	}
+}
+
+// SetBandwidthLimit changes the client-wide upload and download limits in
+// bytes per second, including for transfers already in progress. Zero
+// removes a limit.
+func (c *Client) SetBandwidthLimit(upload, download int64) {
+	c.uploadLimiter.SetLimit(upload)
+	c.downloadLimiter.SetLimit(download)
+}
+
+func (c *Client) throttle(ctx context.Context, dir TransferDirection, r io.Reader, o transferOptions) io.Reader {
+	global := c.uploadLimiter
+	if dir == TransferDownload {
+		global = c.downloadLimiter
+	}
+	limiters := append([]*BandwidthLimiter{global}, o.limiters...)
+	return &throttledReader{ctx: ctx, r: r, limiters: limiters}
+}
+
+type throttledReader struct {
+	ctx      context.Context
+	r        io.Reader
+	limiters []*BandwidthLimiter
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if len(p) > throttleReadSize {
+		p = p[:throttleReadSize]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		for _, l := range t.limiters {
+			if waitErr := l.WaitN(t.ctx, n); waitErr != nil {
+				return n, waitErr
+			}
+		}
+	}
+	return n, err
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
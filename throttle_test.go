@@ -0,0 +1,93 @@
+package yadisk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	b := newTokenBucket(100)
+	if d := b.reserve(100); d != 0 {
+		t.Fatalf("burst reserve waited %s", d)
+	}
+	if d := b.reserve(50); d < 400*time.Millisecond || d > 500*time.Millisecond {
+		t.Fatalf("debt wait = %s", d)
+	}
+
+	b.setRate(0)
+	if d := b.reserve(1 << 20); d != 0 {
+		t.Fatalf("unlimited bucket waited %s", d)
+	}
+}
+
+func TestUploadByLinkRateLimit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusCreated)
+	})
+	link := &ResourceUploadLink{Link: Link{Href: client.transport.baseURL.String() + "/upload", Method: http.MethodPut}}
+
+	start := time.Now()
+	_, err := client.Uploads.UploadByLink(context.Background(), link, bytes.NewReader(make([]byte, 1500)), WithRateLimit(1000))
+	if err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("upload finished in %s, expected throttling", elapsed)
+	}
+}
+
+func TestClientBandwidthLimitAdjustable(t *testing.T) {
+	if _, err := NewClient(WithOAuthToken("x"), WithBandwidthLimit(-1, 0)); err == nil {
+		t.Fatal("expected negative limit error")
+	}
+
+	var baseURL string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/disk/resources/download" {
+			w.Header().Set("Content-Type", "application/json")
+			mustFprint(t, w, `{"href":"`+baseURL+`/file","method":"GET"}`)
+			return
+		}
+		_, _ = w.Write(make([]byte, 3000))
+	})
+	baseURL = client.transport.baseURL.String()
+
+	client.SetBandwidthLimit(0, 1000)
+	if client.downloadLimiter.Limit() != 1000 || client.uploadLimiter.Limit() != 0 {
+		t.Fatalf("limits = %d/%d", client.uploadLimiter.Limit(), client.downloadLimiter.Limit())
+	}
+
+	body, err := client.Uploads.OpenDownload(context.Background(), DownloadURLRequest{Path: "disk:/f"})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer func() { _ = body.Close() }()
+
+	start := time.Now()
+	buf := make([]byte, 1000)
+	if _, err := io.ReadFull(body, buf); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	client.SetBandwidthLimit(0, 0)
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("lifting the limit did not speed up the transfer: %s", elapsed)
+	}
+}
+
+func TestThrottledReaderHonorsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := NewBandwidthLimiter(10)
+	r := &throttledReader{ctx: ctx, r: bytes.NewReader(make([]byte, 100)), limiters: []*BandwidthLimiter{l}}
+	cancel()
+	if _, err := io.ReadAll(r); err == nil {
+		t.Fatal("expected context error")
+	}
+}
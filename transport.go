@@ -14,10 +14,10 @@ import (
 )
 
 type transport struct {
-	httpClient *http.Client
-	baseURL    *url.URL
-	token      string
-	userAgent  string
+	roundTrip RoundTripFunc
+	baseURL   *url.URL
+	token     string
+	userAgent string
 }
 
 func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string) (*http.Request, error) {
@@ -73,6 +73,18 @@ func (c *Client) doJSON(ctx context.Context, method, path string, query url.Valu
 		attempts = 1
 	}
 
+	scope, params := c.scope.forRequest(ctx, path)
+	if scope != nil {
+		mapped, err := scope.mapQuery(ctx, method, path, query, params)
+		if err != nil {
+			return nil, err
+		}
+		query = mapped
+	}
+
+	endpoint := endpointTemplate(path)
+	ctx = context.WithValue(ctx, endpointKey{}, endpoint)
+
 	var lastErr error
 	for attempt := 1; attempt <= attempts; attempt++ {
 		var body io.Reader
@@ -89,19 +101,18 @@ func (c *Client) doJSON(ctx context.Context, method, path string, query url.Valu
 		}
 
 		start := time.Now()
-		resp, err := c.transport.httpClient.Do(req)
+		resp, err := c.transport.roundTrip(req)
 		duration := time.Since(start)
 		if resp != nil && c.hooks.OnResponse != nil {
 			c.hooks.OnResponse(resp, duration)
 		}
+		c.log.request(ctx, req, resp, attempt, duration, false, err)
 
 		if err != nil {
 			lastErr = err
 			if attempt < attempts && isIdempotentMethod(method) {
 				backoff := c.backoff(attempt)
-				if c.hooks.OnRetry != nil {
-					c.hooks.OnRetry(RetryEvent{Attempt: attempt, Method: method, URL: req.URL.String(), Err: err, NextBackoff: backoff})
-				}
+				c.notifyRetry(ctx, RetryEvent{Attempt: attempt, Method: method, Endpoint: endpoint, URL: req.URL.String(), Err: err, NextBackoff: backoff})
 				if err := sleepWithContext(ctx, backoff); err != nil {
 					return nil, err
 				}
@@ -121,16 +132,31 @@ func (c *Client) doJSON(ctx context.Context, method, path string, query url.Valu
 				return nil, err
 			}
 			backoff := c.backoff(attempt)
-			if c.hooks.OnRetry != nil {
-				c.hooks.OnRetry(RetryEvent{Attempt: attempt, Method: method, URL: req.URL.String(), StatusCode: resp.StatusCode, NextBackoff: backoff})
-			}
+			c.notifyRetry(ctx, RetryEvent{Attempt: attempt, Method: method, Endpoint: endpoint, URL: req.URL.String(), StatusCode: resp.StatusCode, NextBackoff: backoff})
 			if err := sleepWithContext(ctx, backoff); err != nil {
 				return nil, err
 			}
 			continue
 		}
 
-		if err := c.decodeResponse(resp, out, expected...); err != nil {
+		if scope == nil || out == nil {
+			if err := c.decodeResponse(resp, out, expected...); err != nil {
+				return resp, err
+			}
+			return resp, nil
+		}
+		var raw json.RawMessage
+		if err := c.decodeResponse(resp, &raw, expected...); err != nil {
+			return resp, err
+		}
+		if len(raw) == 0 {
+			return resp, nil
+		}
+		mapped, err := scope.mapBody(raw)
+		if err != nil {
+			return resp, err
+		}
+		if err := json.Unmarshal(mapped, out); err != nil {
 			return resp, err
 		}
 		return resp, nil
@@ -143,17 +169,31 @@ func (c *Client) doJSON(ctx context.Context, method, path string, query url.Valu
 }
 
 func (c *Client) doRaw(ctx context.Context, req *http.Request) (*http.Response, error) {
+	endpoint := EndpointUpload
+	if req.Method == http.MethodGet {
+		endpoint = EndpointDownload
+	}
+	req = req.WithContext(context.WithValue(req.Context(), endpointKey{}, endpoint))
 	if c.hooks.OnRequest != nil {
 		c.hooks.OnRequest(req)
 	}
 	start := time.Now()
-	resp, err := c.transport.httpClient.Do(req)
+	resp, err := c.transport.roundTrip(req)
+	duration := time.Since(start)
 	if resp != nil && c.hooks.OnResponse != nil {
-		c.hooks.OnResponse(resp, time.Since(start))
+		c.hooks.OnResponse(resp, duration)
 	}
+	c.log.request(ctx, req, resp, 1, duration, true, err)
 	return resp, err
 }
 
+func (c *Client) notifyRetry(ctx context.Context, e RetryEvent) {
+	if c.hooks.OnRetry != nil {
+		c.hooks.OnRetry(e)
+	}
+	c.log.retry(ctx, e)
+}
+
 func (c *Client) decodeResponse(resp *http.Response, out any, expected ...int) error {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
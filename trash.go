@@ -0,0 +1,269 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const trashPageSize = 100
+
+// TrashIterator pages through the trash root. It is used like FileIterator.
+type TrashIterator struct {
+	ctx    context.Context
+	s      *TrashService
+	offset int
+
+	page []TrashResource
+	pos  int
+	cur  TrashResource
+	done bool
+	err  error
+}
+
+// Iterate lists every resource in the trash root, fetching pages lazily.
+func (s *TrashService) Iterate(ctx context.Context) *TrashIterator {
+	return &TrashIterator{ctx: ctx, s: s}
+}
+
+func (it *TrashIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.cur = it.page[it.pos]
+	it.pos++
+	return true
+}
+
+func (it *TrashIterator) fetch() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+	limit, offset := trashPageSize, it.offset
+	res, err := it.s.GetMeta(it.ctx, ResourceGetRequest{Path: "trash:/", Limit: &limit, Offset: &offset})
+	if err != nil {
+		return err
+	}
+	it.page, it.pos = res.Embedded.Items, 0
+	it.offset += len(res.Embedded.Items)
+	if len(res.Embedded.Items) == 0 || it.offset >= res.Embedded.Total {
+		it.done = true
+	}
+	return nil
+}
+
+// Resource returns the trash entry Next advanced to.
+func (it *TrashIterator) Resource() TrashResource {
+	return it.cur
+}
+
+func (it *TrashIterator) Err() error {
+	return it.err
+}
+
+// TrashFilter selects trash entries. Zero fields match everything.
+type TrashFilter struct {
+	// OriginPrefix matches entries deleted from this path or below it.
+	OriginPrefix  string
+	DeletedBefore time.Time
+	DeletedAfter  time.Time
+}
+
+func (f TrashFilter) Match(r TrashResource) bool {
+	if f.OriginPrefix != "" && !underPath(r.OriginPath, f.OriginPrefix) {
+		return false
+	}
+	if !f.DeletedBefore.IsZero() && !r.Deleted.Time.Before(f.DeletedBefore) {
+		return false
+	}
+	if !f.DeletedAfter.IsZero() && !r.Deleted.Time.After(f.DeletedAfter) {
+		return false
+	}
+	return true
+}
+
+// List returns the trash entries matching f, oldest deletion first.
+func (s *TrashService) List(ctx context.Context, f TrashFilter) (_ []TrashResource, err error) {
+	ctx, end := s.client.startCall(ctx, "Trash.List")
+	defer func() { end(err) }()
+
+	var out []TrashResource
+	it := s.Iterate(ctx)
+	for it.Next() {
+		if r := it.Resource(); f.Match(r) {
+			out = append(out, r)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Deleted.Time.Before(out[j].Deleted.Time) })
+	return out, nil
+}
+
+// RestoreConflictPolicy decides what a bulk restore does when something
+// already exists at an entry's origin path.
+type RestoreConflictPolicy int
+
+const (
+	// RestoreSkip leaves the entry in the trash.
+	RestoreSkip RestoreConflictPolicy = iota
+	// RestoreOverwrite replaces the existing resource.
+	RestoreOverwrite
+	// RestoreRename restores next to it as "name (restored N).ext".
+	RestoreRename
+	// RestoreFail records the conflict as the entry's error.
+	RestoreFail
+)
+
+type TrashItemResult struct {
+	// Path is the entry's trash path.
+	Path       string
+	OriginPath string
+	// RestoredAs is the disk path a restored entry ended up at.
+	RestoredAs string
+	Result     ActionResult
+	Skipped    bool
+	Err        error
+}
+
+type TrashReport struct {
+	Items []TrashItemResult
+}
+
+func (r *TrashReport) Failed() []TrashItemResult {
+	var out []TrashItemResult
+	for _, item := range r.Items {
+		if item.Err != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (r *TrashReport) err(verb string) error {
+	var errs []error
+	for _, item := range r.Items {
+		if item.Err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", verb, item.Path, item.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PurgeOlderThan permanently deletes trash entries deleted more than age
+// ago. The returned error joins the per-entry failures.
+func (s *TrashService) PurgeOlderThan(ctx context.Context, age time.Duration) (_ *TrashReport, err error) {
+	ctx, end := s.client.startCall(ctx, "Trash.PurgeOlderThan")
+	defer func() { end(err) }()
+
+	entries, err := s.List(ctx, TrashFilter{DeletedBefore: time.Now().Add(-age)})
+	if err != nil {
+		return nil, err
+	}
+	report := &TrashReport{Items: make([]TrashItemResult, 0, len(entries))}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		item := TrashItemResult{Path: e.Path, OriginPath: e.OriginPath}
+		item.Result, item.Err = s.Empty(ctx, TrashDeleteRequest{Path: e.Path})
+		report.Items = append(report.Items, item)
+	}
+	return report, report.err("purge")
+}
+
+// RestoreByOriginPrefix restores every trash entry deleted from prefix or
+// below it, oldest deletion first, resolving clashes with existing resources
+// by conflict. Asynchronous restores are returned in each item's Result
+// without being waited for.
+func (s *TrashService) RestoreByOriginPrefix(ctx context.Context, prefix string, conflict RestoreConflictPolicy) (_ *TrashReport, err error) {
+	ctx, end := s.client.startCall(ctx, "Trash.RestoreByOriginPrefix")
+	defer func() { end(err) }()
+
+	if prefix == "" {
+		return nil, errors.New("origin prefix is required")
+	}
+	entries, err := s.List(ctx, TrashFilter{OriginPrefix: prefix})
+	if err != nil {
+		return nil, err
+	}
+	report := &TrashReport{Items: make([]TrashItemResult, 0, len(entries))}
+	for _, e := range entries {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		report.Items = append(report.Items, s.restoreEntry(ctx, e, conflict))
+	}
+	return report, report.err("restore")
+}
+
+func (s *TrashService) restoreEntry(ctx context.Context, e TrashResource, conflict RestoreConflictPolicy) TrashItemResult {
+	item := TrashItemResult{Path: e.Path, OriginPath: e.OriginPath}
+	req := TrashRestoreRequest{Path: e.Path}
+	item.Result, item.Err = s.Restore(ctx, req)
+	if item.Err == nil {
+		item.RestoredAs = e.OriginPath
+	}
+	if !isConflict(item.Err) {
+		return item
+	}
+
+	switch conflict {
+	case RestoreSkip:
+		item.Skipped, item.Err = true, nil
+	case RestoreOverwrite:
+		overwrite := true
+		req.Overwrite = &overwrite
+		item.Result, item.Err = s.Restore(ctx, req)
+		if item.Err == nil {
+			item.RestoredAs = e.OriginPath
+		}
+	case RestoreRename:
+		name := path.Base(stripDiskScheme(e.OriginPath))
+		ext := path.Ext(name)
+		for n := 1; n <= 100 && isConflict(item.Err); n++ {
+			req.Name = strings.TrimSuffix(name, ext) + " (restored" + restoredSuffix(n) + ")" + ext
+			item.Result, item.Err = s.Restore(ctx, req)
+		}
+		if item.Err == nil {
+			item.RestoredAs = path.Join(path.Dir(e.OriginPath), req.Name)
+		}
+	}
+	return item
+}
+
+func restoredSuffix(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return " " + strconv.Itoa(n)
+}
+
+// underPath reports whether p is root or below it in the same scheme. An
+// empty root contains everything.
+func underPath(p, root string) bool {
+	if root == "" {
+		return true
+	}
+	rootPath, err := ParsePath(root)
+	if err != nil {
+		return false
+	}
+	target, err := ParsePath(p)
+	return err == nil && rootPath.Contains(target)
+}
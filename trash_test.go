@@ -0,0 +1,80 @@
+package yadisk
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTrashListAndPurge(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	now := time.Now()
+	srv.PutTrash("disk:/docs/old.txt", []byte("o"), now.Add(-40*24*time.Hour))
+	srv.PutTrash("disk:/docs/new.txt", []byte("n"), now.Add(-time.Hour))
+	srv.PutTrash("disk:/other/x.txt", []byte("x"), now.Add(-50*24*time.Hour))
+
+	n := 0
+	it := client.Trash.Iterate(ctx)
+	for it.Next() {
+		n++
+	}
+	if err := it.Err(); err != nil || n != 3 {
+		t.Fatalf("iterate = %d, %v", n, err)
+	}
+
+	docs, err := client.Trash.List(ctx, TrashFilter{OriginPrefix: "disk:/docs"})
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(docs) != 2 || docs[0].OriginPath != "disk:/docs/old.txt" {
+		t.Fatalf("docs = %+v", docs)
+	}
+
+	report, err := client.Trash.PurgeOlderThan(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if len(report.Items) != 2 || srv.Trashed("disk:/docs/old.txt") || srv.Trashed("disk:/other/x.txt") || !srv.Trashed("disk:/docs/new.txt") {
+		t.Fatalf("purge report = %+v", report.Items)
+	}
+}
+
+func TestTrashRestoreByOriginPrefix(t *testing.T) {
+	for _, tc := range []struct {
+		policy   RestoreConflictPolicy
+		restored string
+		content  string
+		failed   bool
+	}{
+		{policy: RestoreSkip, content: "current"},
+		{policy: RestoreOverwrite, restored: "disk:/docs/a.txt", content: "trashed"},
+		{policy: RestoreRename, restored: "disk:/docs/a (restored).txt", content: "current"},
+		{policy: RestoreFail, content: "current", failed: true},
+	} {
+		client, srv := newFakeDiskClient(t)
+		ctx := context.Background()
+		srv.PutTrash("disk:/docs/a.txt", []byte("trashed"), time.Now().Add(-time.Hour))
+		srv.PutTrash("disk:/docs/sub/b.txt", []byte("b"), time.Now())
+		srv.PutTrash("disk:/keep/c.txt", []byte("c"), time.Now())
+		srv.PutFile("disk:/docs/a.txt", []byte("current"), time.Now())
+
+		report, err := client.Trash.RestoreByOriginPrefix(ctx, "disk:/docs", tc.policy)
+		if (err != nil) != tc.failed {
+			t.Fatalf("policy %d: restore err = %v", tc.policy, err)
+		}
+		if len(report.Items) != 2 || !srv.Exists("disk:/docs/sub/b.txt") || !srv.Trashed("disk:/keep/c.txt") {
+			t.Fatalf("policy %d: report = %+v", tc.policy, report.Items)
+		}
+		a := report.Items[0]
+		if a.RestoredAs != tc.restored || a.Skipped != (tc.policy == RestoreSkip) {
+			t.Fatalf("policy %d: a.txt = %+v", tc.policy, a)
+		}
+		if data, _ := srv.File("disk:/docs/a.txt"); string(data) != tc.content {
+			t.Fatalf("policy %d: a.txt content = %q", tc.policy, data)
+		}
+		if tc.restored != "" && !srv.Exists(tc.restored) {
+			t.Fatalf("policy %d: %s missing", tc.policy, tc.restored)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MergePolicy decides what CopyTree and MoveTree do with a file that already
+// exists at the destination.
+type MergePolicy int
+
+const (
+	// MergeSkipExisting keeps the destination file.
+	MergeSkipExisting MergePolicy = iota
+	// MergeOverwriteNewer replaces the destination file when the source was
+	// modified later.
+	MergeOverwriteNewer
+	// MergeOverwriteDifferent replaces the destination file when its MD5 or
+	// size differs from the source.
+	MergeOverwriteDifferent
+)
+
+type TreeConfig struct {
+	Policy MergePolicy
+	// Concurrency bounds the file operations in flight. Defaults to 4.
+	Concurrency int
+	// OnItem is called from the worker goroutines as each file finishes.
+	OnItem func(TreeItem)
+}
+
+type TreeItem struct {
+	From string
+	Path string
+	Size int64
+	// Overwrote is set when an existing destination file was replaced.
+	Overwrote bool
+	Skipped   bool
+	Result    ActionResult
+	Err       error
+}
+
+type TreeReport struct {
+	Items []TreeItem
+}
+
+func (r *TreeReport) Failed() []TreeItem {
+	var out []TreeItem
+	for _, item := range r.Items {
+		if item.Err != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (r *TreeReport) Skipped() int {
+	n := 0
+	for _, item := range r.Items {
+		if item.Skipped {
+			n++
+		}
+	}
+	return n
+}
+
+// CopyTree merges the folder from into the folder to file by file, creating
+// to and any missing subfolders. Files that exist on both sides are handled
+// according to cfg.Policy; destination files absent from the source are
+// left alone. Each file is copied server-side and asynchronous operations
+// are followed through Client.Worker. The report covers every source file;
+// the returned error joins the per-file failures.
+func (s *ResourcesService) CopyTree(ctx context.Context, from, to string, cfg TreeConfig) (_ *TreeReport, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.CopyTree")
+	defer func() { end(err) }()
+
+	report, _, err := s.mergeTree(ctx, from, to, cfg, s.Copy)
+	return report, err
+}
+
+// MoveTree is like CopyTree but moves each file. Once every file has been
+// moved, the source folder is moved to the trash if it no longer holds any
+// files; files added to it meanwhile keep it in place, as do skipped or
+// failed files. A source without files is left alone.
+func (s *ResourcesService) MoveTree(ctx context.Context, from, to string, cfg TreeConfig) (_ *TreeReport, err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.MoveTree")
+	defer func() { end(err) }()
+
+	report, files, err := s.mergeTree(ctx, from, to, cfg, s.Move)
+	if err != nil || report.Skipped() > 0 || files == 0 {
+		return report, err
+	}
+	if len(report.Items) != files {
+		return report, fmt.Errorf("source %s kept: %d of %d files were moved", from, len(report.Items), files)
+	}
+	left := 0
+	err = s.Walk(ctx, from, func(r Resource) error {
+		if r.Type != "dir" {
+			left++
+		}
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("check source %s: %w", from, err)
+	}
+	if left > 0 {
+		return report, fmt.Errorf("source %s kept: it holds %d files added during the move", from, left)
+	}
+	permanently := false
+	res, err := s.Delete(ctx, DeleteResourceRequest{Path: from, Permanently: &permanently})
+	if err == nil && res.Operation != nil {
+		err = s.client.finishOperation(ctx, *res.Operation)
+	}
+	if err != nil {
+		return report, fmt.Errorf("delete source %s: %w", from, err)
+	}
+	return report, nil
+}
+
+type treeFile struct {
+	src       Resource
+	dest      string
+	overwrite bool
+}
+
+// mergeTree runs call for every file below from and returns the report and
+// the number of source files found.
+func (s *ResourcesService) mergeTree(ctx context.Context, from, to string, cfg TreeConfig, call func(context.Context, CopyMoveRequest) (ActionResult, error)) (*TreeReport, int, error) {
+	if from == "" || to == "" {
+		return nil, 0, errors.New("from and to are required")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaultBatchConcurrency
+	}
+	fromPath, err := s.treeRoot(ctx, from)
+	if err != nil {
+		return nil, 0, err
+	}
+	toPath, err := s.treeRoot(ctx, to)
+	if err != nil {
+		return nil, 0, err
+	}
+	if fromPath.Contains(toPath) {
+		return nil, 0, fmt.Errorf("%s is inside %s", to, from)
+	}
+	from, to = fromPath.String(), toPath.String()
+	root, err := s.GetMeta(ctx, ResourceGetRequest{Path: from, Fields: []string{"type"}})
+	if err != nil {
+		return nil, 0, err
+	}
+	if root.Type != "dir" {
+		return nil, 0, fmt.Errorf("%s is not a folder", from)
+	}
+
+	existing := make(map[string]Resource)
+	err = s.Walk(ctx, to, func(r Resource) error {
+		rel, ok := relativeRemotePath(to, r.Path)
+		if !ok {
+			return fmt.Errorf("listing of %s returned %s", to, r.Path)
+		}
+		existing[rel] = r
+		return nil
+	})
+	if err != nil && !isNotFound(err) {
+		return nil, 0, err
+	}
+	if err := s.MkdirAll(ctx, to); err != nil {
+		return nil, 0, err
+	}
+
+	report := &TreeReport{}
+	var files []treeFile
+	found := 0
+	err = s.Walk(ctx, from, func(r Resource) error {
+		rel, ok := relativeRemotePath(from, r.Path)
+		if !ok {
+			return fmt.Errorf("listing of %s returned %s", from, r.Path)
+		}
+		if r.Type != "dir" {
+			found++
+		}
+		dest := toPath.Join(rel).String()
+		have, exists := existing[rel]
+		if r.Type == "dir" {
+			if exists && have.Type == "dir" {
+				return nil
+			}
+			return s.MkdirAll(ctx, dest)
+		}
+		f := treeFile{src: r, dest: dest}
+		if exists {
+			if have.Type == "dir" {
+				report.Items = append(report.Items, TreeItem{From: r.Path, Path: dest, Size: r.Size, Err: fmt.Errorf("%s is a folder", dest)})
+				return nil
+			}
+			if !cfg.Policy.replaces(r, have) {
+				report.Items = append(report.Items, TreeItem{From: r.Path, Path: dest, Size: r.Size, Skipped: true})
+				return nil
+			}
+			f.overwrite = true
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return report, found, err
+	}
+	if cfg.OnItem != nil {
+		for _, item := range report.Items {
+			cfg.OnItem(item)
+		}
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		jobs = make(chan treeFile)
+	)
+	for w := 0; w < cfg.Concurrency && w < len(files); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range jobs {
+				item := TreeItem{From: f.src.Path, Path: f.dest, Size: f.src.Size, Overwrote: f.overwrite}
+				overwrite := f.overwrite
+				item.Result, item.Err = call(ctx, CopyMoveRequest{From: f.src.Path, Path: f.dest, Overwrite: &overwrite})
+				if item.Err == nil && item.Result.Operation != nil {
+					item.Err = s.client.finishOperation(ctx, *item.Result.Operation)
+				}
+				if cfg.OnItem != nil {
+					cfg.OnItem(item)
+				}
+				mu.Lock()
+				report.Items = append(report.Items, item)
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, f := range files {
+		select {
+		case jobs <- f:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return report, found, err
+	}
+	var errs []error
+	for _, item := range report.Items {
+		if item.Err != nil {
+			errs = append(errs, fmt.Errorf("%s -> %s: %w", item.From, item.Path, item.Err))
+		}
+	}
+	return report, found, errors.Join(errs...)
+}
+
+// treeRoot parses p and resolves an "app:" path to the "disk:" form that
+// listings report, through the application folder, which exists whenever
+// anything below it does.
+func (s *ResourcesService) treeRoot(ctx context.Context, p string) (Path, error) {
+	parsed, err := ParsePath(p)
+	if err != nil || parsed.Scheme() != SchemeApp {
+		return parsed, err
+	}
+	resolved, err := s.client.resolveRemotePath(ctx, AppPath().String())
+	if err != nil {
+		return Path{}, err
+	}
+	appRoot, err := ParsePath(resolved)
+	if err != nil {
+		return Path{}, err
+	}
+	return appRoot.Join(parsed.p), nil
+}
+
+// replaces reports whether src should overwrite the existing dst.
+func (p MergePolicy) replaces(src, dst Resource) bool {
+	switch p {
+	case MergeOverwriteNewer:
+		return src.Modified.Time.After(dst.Modified.Time)
+	case MergeOverwriteDifferent:
+		return src.Size != dst.Size || src.MD5 != dst.MD5
+	default:
+		return false
+	}
+}
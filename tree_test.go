@@ -0,0 +1,118 @@
+package yadisk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grixate/yandex-disk-go-v2/internal/fakedisk"
+)
+
+func TestCopyTreeMergePolicies(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	recent := time.Now()
+	for _, tc := range []struct {
+		policy MergePolicy
+		want   map[string]string
+	}{
+		{MergeSkipExisting, map[string]string{"same": "same", "newer": "dst", "older": "dst", "new": "src"}},
+		{MergeOverwriteNewer, map[string]string{"same": "same", "newer": "src", "older": "dst", "new": "src"}},
+		{MergeOverwriteDifferent, map[string]string{"same": "same", "newer": "src", "older": "src", "new": "src"}},
+	} {
+		client, srv := newFakeDiskClient(t)
+		srv.PutFile("disk:/src/same", []byte("same"), recent)
+		srv.PutFile("disk:/src/sub/newer", []byte("src"), recent)
+		srv.PutFile("disk:/src/older", []byte("src"), old)
+		srv.PutFile("disk:/src/sub/deep/new", []byte("src"), old)
+		srv.Mkdir("disk:/src/empty")
+		srv.PutFile("disk:/dst/same", []byte("same"), old)
+		srv.PutFile("disk:/dst/sub/newer", []byte("dst"), old)
+		srv.PutFile("disk:/dst/older", []byte("dst"), recent)
+		srv.PutFile("disk:/dst/keep", []byte("keep"), old)
+
+		report, err := client.Resources.CopyTree(context.Background(), "disk:/src", "disk:/dst", TreeConfig{Policy: tc.policy, Concurrency: 2})
+		if err != nil {
+			t.Fatalf("policy %d: copy tree: %v", tc.policy, err)
+		}
+		if len(report.Items) != 4 {
+			t.Fatalf("policy %d: items = %+v", tc.policy, report.Items)
+		}
+		for name, want := range tc.want {
+			p := "disk:/dst/" + name
+			switch name {
+			case "newer":
+				p = "disk:/dst/sub/newer"
+			case "new":
+				p = "disk:/dst/sub/deep/new"
+			}
+			if got, _ := srv.File(p); string(got) != want {
+				t.Fatalf("policy %d: %s = %q, want %q", tc.policy, p, got, want)
+			}
+		}
+		if !srv.Exists("disk:/dst/keep") || !srv.Exists("disk:/dst/empty") || !srv.Exists("disk:/src/same") {
+			t.Fatalf("policy %d: tree not merged", tc.policy)
+		}
+	}
+}
+
+func TestMoveTree(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	srv.Async = true
+	ctx := context.Background()
+	srv.PutFile("disk:/src/a", []byte("a"), time.Now())
+	srv.PutFile("disk:/src/sub/b", []byte("b"), time.Now())
+	srv.PutFile("disk:/dst/sub/c", []byte("c"), time.Now())
+	t.Cleanup(func() { _ = client.Close(ctx) })
+
+	report, err := client.Resources.MoveTree(ctx, "disk:/src", "disk:/dst", TreeConfig{})
+	if err != nil || len(report.Failed()) != 0 {
+		t.Fatalf("move tree: %v", err)
+	}
+	if !srv.Exists("disk:/dst/a") || !srv.Exists("disk:/dst/sub/b") || !srv.Exists("disk:/dst/sub/c") || srv.Exists("disk:/src") {
+		t.Fatal("tree not moved")
+	}
+	if !srv.Trashed("disk:/src") {
+		t.Fatal("source folder not moved to the trash")
+	}
+
+	if _, err := client.Resources.CopyTree(ctx, "disk:/dst", "disk:/dst/sub", TreeConfig{}); err == nil {
+		t.Fatal("copy into own subtree accepted")
+	}
+}
+
+func TestMoveTreeAppFolder(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile(fakedisk.AppFolder+"/src/a", []byte("a"), time.Now())
+	srv.PutFile(fakedisk.AppFolder+"/src/sub/b", []byte("b"), time.Now())
+
+	report, err := client.Resources.MoveTree(ctx, "app:/src", "app:/dst", TreeConfig{})
+	if err != nil {
+		t.Fatalf("move tree: %v", err)
+	}
+	if len(report.Items) != 2 {
+		t.Fatalf("report = %+v", report.Items)
+	}
+	if !srv.Exists(fakedisk.AppFolder+"/dst/a") || !srv.Exists(fakedisk.AppFolder+"/dst/sub/b") || srv.Exists(fakedisk.AppFolder+"/src") {
+		t.Fatal("tree not moved")
+	}
+	if _, err := client.Resources.CopyTree(ctx, "app:/dst", fakedisk.AppFolder+"/dst/sub", TreeConfig{}); err == nil {
+		t.Fatal("copy into own subtree accepted")
+	}
+}
+
+func TestMoveTreeKeepsFilesAddedDuringTheMove(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	srv.PutFile("disk:/src/a", []byte("a"), time.Now())
+
+	report, err := client.Resources.MoveTree(ctx, "disk:/src", "disk:/dst", TreeConfig{
+		OnItem: func(TreeItem) { srv.PutFile("disk:/src/late", []byte("l"), time.Now()) },
+	})
+	if err == nil {
+		t.Fatal("source with a new file deleted")
+	}
+	if len(report.Items) != 1 || !srv.Exists("disk:/dst/a") || !srv.Exists("disk:/src/late") {
+		t.Fatalf("report = %+v", report.Items)
+	}
+}
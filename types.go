@@ -171,9 +171,10 @@ type Resource struct {
 
 type PublicResource struct {
 	BaseResource
-	ViewsCount int            `json:"views_count"`
-	Owner      Owner          `json:"owner"`
-	Embedded   PublicEmbedded `json:"_embedded"`
+	ViewsCount     int             `json:"views_count"`
+	Owner          Owner           `json:"owner"`
+	Embedded       PublicEmbedded  `json:"_embedded"`
+	PublicSettings *PublicSettings `json:"public_settings,omitempty"`
 }
 
 type TrashResource struct {
@@ -283,6 +284,8 @@ type ResourceUpdateRequest struct {
 	Path             string
 	Fields           []string
 	CustomProperties map[string]any
+	// IfMatch makes the update conditional on the resource's current state.
+	IfMatch *Precondition
 }
 
 type CreateFolderRequest struct {
@@ -296,6 +299,9 @@ type CopyMoveRequest struct {
 	Fields     []string
 	ForceAsync *bool
 	Overwrite  *bool
+	// IfMatch makes the call conditional on the destination being
+	// overwritten. Set Overwrite as well.
+	IfMatch *Precondition
 }
 
 type DeleteResourceRequest struct {
@@ -304,17 +310,63 @@ type DeleteResourceRequest struct {
 	ForceAsync  *bool
 	MD5         string
 	Permanently *bool
+	// IfMatch makes the delete conditional. Its MD5 is sent as the md5
+	// parameter when MD5 is empty.
+	IfMatch *Precondition
 }
 
 type PublishRequest struct {
 	Path   string
 	Fields []string
+	// AllowAddressAccess lets Accesses grant rights to specific users by
+	// address. Only used when publishing.
+	AllowAddressAccess *bool
+	// PublicSettings configures the link when publishing. Unpublish ignores
+	// it.
+	PublicSettings *PublicSettings
+}
+
+// PublicSettings configures a public link. Zero fields are left unchanged
+// by Resources.UpdatePublicSettings.
+type PublicSettings struct {
+	ReadOnly *bool  `json:"read_only,omitempty"`
+	Password string `json:"password,omitempty"`
+	// AvailableUntil is when the link stops working, in Unix seconds.
+	AvailableUntil         int64          `json:"available_until,omitempty"`
+	Accesses               []PublicAccess `json:"accesses,omitempty"`
+	ExternalOrganizationID string         `json:"external_organization_id,omitempty"`
+}
+
+// PublicAccess grants rights on a public link, either to a macro audience
+// such as "all" or "employees", or to a user, group or department by ID.
+type PublicAccess struct {
+	Type   string   `json:"type"`
+	Macros []string `json:"macros,omitempty"`
+	OrgID  int64    `json:"org_id,omitempty"`
+	ID     int64    `json:"id,omitempty"`
+	Rights []string `json:"rights,omitempty"`
+}
+
+// AvailableUntilTime returns AvailableUntil as a time, or the zero time when
+// the link does not expire.
+func (p PublicSettings) AvailableUntilTime() time.Time {
+	if p.AvailableUntil == 0 {
+		return time.Time{}
+	}
+	return time.Unix(p.AvailableUntil, 0)
+}
+
+type publishPayload struct {
+	PublicSettings *PublicSettings `json:"public_settings,omitempty"`
 }
 
 type UploadURLRequest struct {
 	Path      string
 	Fields    []string
 	Overwrite *bool
+	// IfMatch is checked before the upload link is requested, so the upload
+	// only replaces the file version the caller read. Set Overwrite as well.
+	IfMatch *Precondition
 }
 
 type UploadExternalRequest struct {
@@ -0,0 +1,71 @@
+package yadisk
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+)
+
+const walkPageSize = 100
+
+// Walk calls fn for every resource below root, depth first. As with
+// filepath.WalkDir, returning fs.SkipDir skips a directory's contents, or the
+// rest of the parent directory when returned for a file. Listings are read
+// from the server even when the client caches metadata.
+func (s *ResourcesService) Walk(ctx context.Context, root string, fn func(Resource) error) (err error) {
+	ctx, end := s.client.startCall(ctx, "Resources.Walk")
+	defer func() { end(err) }()
+
+	if root == "" {
+		return errors.New("root is required")
+	}
+	if fn == nil {
+		return errors.New("walk func is required")
+	}
+	return s.walkDir(uncached(ctx), root, fn)
+}
+
+func (s *ResourcesService) walkDir(ctx context.Context, dir string, fn func(Resource) error) error {
+	limit := walkPageSize
+	offset := 0
+	for {
+		page := offset
+		res, err := s.GetMeta(ctx, ResourceGetRequest{Path: dir, Limit: &limit, Offset: &page})
+		if err != nil {
+			return err
+		}
+
+		for _, item := range res.Embedded.Items {
+			err := fn(item)
+			if errors.Is(err, fs.SkipDir) {
+				if item.Type == "dir" {
+					continue
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if item.Type == "dir" {
+				if err := s.walkDir(ctx, item.Path, fn); err != nil {
+					return err
+				}
+			}
+		}
+
+		offset += len(res.Embedded.Items)
+		if len(res.Embedded.Items) == 0 || offset >= res.Embedded.Total {
+			return nil
+		}
+	}
+}
+
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatus == 404
+}
+
+func isConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatus == 409
+}
@@ -0,0 +1,306 @@
+package yadisk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchInterval = 30 * time.Second
+	defaultWatchBuffer   = 64
+	watchStateVersion    = 1
+)
+
+type WatchEventType string
+
+const (
+	WatchCreated  WatchEventType = "created"
+	WatchModified WatchEventType = "modified"
+	WatchDeleted  WatchEventType = "deleted"
+	WatchMoved    WatchEventType = "moved"
+	// WatchError reports a failed poll. The watcher keeps polling.
+	WatchError WatchEventType = "error"
+)
+
+type WatchEvent struct {
+	Type WatchEventType
+	Path string
+	// OldPath is the previous location of a moved resource.
+	OldPath string
+	// Resource is the latest known state; for deletions, the last one seen.
+	Resource WatchedResource
+	Err      error
+}
+
+// WatchedResource is what a Watcher remembers about each resource between
+// polls.
+type WatchedResource struct {
+	ResourceID string    `json:"resource_id,omitempty"`
+	Type       string    `json:"type"`
+	Size       int64     `json:"size,omitempty"`
+	MD5        string    `json:"md5,omitempty"`
+	Modified   time.Time `json:"modified"`
+}
+
+type WatcherConfig struct {
+	// Root is the folder to watch, recursively.
+	Root string
+	// Interval between polls. Defaults to 30 seconds.
+	Interval time.Duration
+	// StatePath persists the last snapshot as JSON so a restarted watcher
+	// only reports what changed while it was down. Empty keeps it in memory.
+	StatePath string
+	// EmitInitial reports every existing resource as created on the first
+	// poll without a saved snapshot. By default that poll is silent.
+	EmitInitial bool
+	// NoRevisionCheck walks the tree on every poll instead of skipping the
+	// walk while DiskInfo.Revision is unchanged.
+	NoRevisionCheck bool
+	// Buffer is the capacity of the events channel. Defaults to 64.
+	Buffer int
+}
+
+// Watcher detects changes below a folder by periodically diffing snapshots
+// of the tree, as the REST API has no push notifications.
+type Watcher struct {
+	client *Client
+	cfg    WatcherConfig
+	events chan WatchEvent
+
+	mu    sync.Mutex
+	state *watchSnapshot
+}
+
+type watchSnapshot struct {
+	Version  int                        `json:"version"`
+	Root     string                     `json:"root"`
+	Revision int64                      `json:"revision"`
+	Entries  map[string]WatchedResource `json:"entries"`
+}
+
+func (c *Client) NewWatcher(cfg WatcherConfig) (*Watcher, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("root is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultWatchInterval
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaultWatchBuffer
+	}
+	w := &Watcher{client: c, cfg: cfg, events: make(chan WatchEvent, cfg.Buffer)}
+	if cfg.StatePath != "" {
+		state, err := loadWatchSnapshot(cfg.StatePath)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil && state.Root == cfg.Root {
+			w.state = state
+		}
+	}
+	return w, nil
+}
+
+// Events is closed when Run returns.
+func (w *Watcher) Events() <-chan WatchEvent {
+	return w.events
+}
+
+// Run polls until ctx is done, sending events to Events. It returns
+// ctx.Err().
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.events)
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		events, err := w.Poll(ctx)
+		if err != nil && ctx.Err() == nil {
+			events = []WatchEvent{{Type: WatchError, Path: w.cfg.Root, Err: err}}
+		}
+		for _, e := range events {
+			select {
+			case w.events <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll takes one snapshot and returns the changes since the previous one.
+// It may be used instead of Run to drive the watcher manually.
+func (w *Watcher) Poll(ctx context.Context) ([]WatchEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var revision int64
+	if !w.cfg.NoRevisionCheck {
+		info, err := w.client.Disk.Get(ctx, DiskGetRequest{Fields: []string{"revision"}})
+		if err != nil {
+			return nil, err
+		}
+		revision = info.Revision
+		if w.state != nil && revision != 0 && revision == w.state.Revision {
+			return nil, nil
+		}
+	}
+
+	entries := make(map[string]WatchedResource)
+	err := w.client.Resources.Walk(ctx, w.cfg.Root, func(r Resource) error {
+		entries[r.Path] = WatchedResource{
+			ResourceID: r.ResourceID,
+			Type:       r.Type,
+			Size:       r.Size,
+			MD5:        r.MD5,
+			Modified:   r.Modified.Time,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []WatchEvent
+	if w.state != nil {
+		events = diffWatchSnapshots(w.state.Entries, entries)
+	} else if w.cfg.EmitInitial {
+		events = diffWatchSnapshots(nil, entries)
+	}
+	next := &watchSnapshot{Version: watchStateVersion, Root: w.cfg.Root, Revision: revision, Entries: entries}
+	if w.cfg.StatePath != "" {
+		if err := saveWatchSnapshot(w.cfg.StatePath, next); err != nil {
+			return nil, err
+		}
+	}
+	w.state = next
+	return events, nil
+}
+
+func diffWatchSnapshots(prev, next map[string]WatchedResource) []WatchEvent {
+	var created, deleted, events []WatchEvent
+	for p, cur := range next {
+		old, ok := prev[p]
+		switch {
+		case !ok:
+			created = append(created, WatchEvent{Type: WatchCreated, Path: p, Resource: cur})
+		case old.Type != cur.Type:
+			deleted = append(deleted, WatchEvent{Type: WatchDeleted, Path: p, Resource: old})
+			created = append(created, WatchEvent{Type: WatchCreated, Path: p, Resource: cur})
+		case cur.Type != "dir" && (old.MD5 != cur.MD5 || old.Size != cur.Size || !old.Modified.Equal(cur.Modified)):
+			events = append(events, WatchEvent{Type: WatchModified, Path: p, Resource: cur})
+		}
+	}
+	for p, old := range prev {
+		if _, ok := next[p]; !ok {
+			deleted = append(deleted, WatchEvent{Type: WatchDeleted, Path: p, Resource: old})
+		}
+	}
+
+	moves, created, deleted := pairWatchMoves(created, deleted)
+	events = append(events, moves...)
+	events = append(events, created...)
+	events = append(events, deleted...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+// pairWatchMoves turns a deletion and a creation of the same resource into a
+// move. Resources are matched by resource_id, or for files without one by
+// content when that is unambiguous. Moves implied by a moved parent folder
+// are folded into the parent's event.
+func pairWatchMoves(created, deleted []WatchEvent) (moves, restCreated, restDeleted []WatchEvent) {
+	key := func(r WatchedResource) string {
+		if r.ResourceID != "" {
+			return "id:" + r.ResourceID
+		}
+		if r.Type == "file" && r.MD5 != "" {
+			return fmt.Sprintf("md5:%s:%d", r.MD5, r.Size)
+		}
+		return ""
+	}
+	byKey := make(map[string][]int)
+	for i, e := range deleted {
+		if k := key(e.Resource); k != "" {
+			byKey[k] = append(byKey[k], i)
+		}
+	}
+	usedDeleted := make(map[int]bool)
+	for _, e := range created {
+		k := key(e.Resource)
+		if cands := byKey[k]; k != "" && len(cands) == 1 && !usedDeleted[cands[0]] {
+			usedDeleted[cands[0]] = true
+			moves = append(moves, WatchEvent{Type: WatchMoved, Path: e.Path, OldPath: deleted[cands[0]].Path, Resource: e.Resource})
+			continue
+		}
+		restCreated = append(restCreated, e)
+	}
+	for i, e := range deleted {
+		if !usedDeleted[i] {
+			restDeleted = append(restDeleted, e)
+		}
+	}
+
+	sort.Slice(moves, func(i, j int) bool { return moves[i].OldPath < moves[j].OldPath })
+	var folded []WatchEvent
+	for _, m := range moves {
+		implied := false
+		for _, dir := range folded {
+			if dir.Resource.Type == "dir" && strings.HasPrefix(m.OldPath, dir.OldPath+"/") &&
+				m.Path == dir.Path+strings.TrimPrefix(m.OldPath, dir.OldPath) {
+				implied = true
+				break
+			}
+		}
+		if !implied {
+			folded = append(folded, m)
+		}
+	}
+	return folded, restCreated, restDeleted
+}
+
+func loadWatchSnapshot(name string) (*watchSnapshot, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := new(watchSnapshot)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode watch state %s: %w", name, err)
+	}
+	if state.Entries == nil {
+		state.Entries = make(map[string]WatchedResource)
+	}
+	return state, nil
+}
+
+func saveWatchSnapshot(name string, state *watchSnapshot) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,159 @@
+package yadisk
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func watchSummary(events []WatchEvent) map[string]WatchEventType {
+	out := make(map[string]WatchEventType)
+	for _, e := range events {
+		key := e.Path
+		if e.OldPath != "" {
+			key = e.OldPath + " -> " + e.Path
+		}
+		out[key] = e.Type
+	}
+	return out
+}
+
+func TestWatcherPollDiffs(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	srv.PutFile("disk:/w/keep.txt", []byte("keep"), base)
+	srv.PutFile("disk:/w/edit.txt", []byte("v1"), base)
+	srv.PutFile("disk:/w/gone.txt", []byte("gone"), base)
+	srv.PutFile("disk:/w/dir/inner.txt", []byte("inner"), base)
+	srv.PutFile("disk:/w/file.txt", []byte("file"), base)
+
+	w, err := client.NewWatcher(WatcherConfig{Root: "disk:/w"})
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+	if events, err := w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Fatalf("baseline poll = %v, %v", events, err)
+	}
+	if events, err := w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Fatalf("unchanged poll = %v, %v", events, err)
+	}
+
+	srv.PutFile("disk:/w/new.txt", []byte("new"), base)
+	srv.PutFile("disk:/w/edit.txt", []byte("v2"), base.Add(time.Hour))
+	srv.Remove("disk:/w/gone.txt")
+	if _, err := client.Resources.Move(ctx, CopyMoveRequest{From: "disk:/w/dir", Path: "disk:/w/moved"}); err != nil {
+		t.Fatalf("move dir: %v", err)
+	}
+	if _, err := client.Resources.Move(ctx, CopyMoveRequest{From: "disk:/w/file.txt", Path: "disk:/w/renamed.txt"}); err != nil {
+		t.Fatalf("move file: %v", err)
+	}
+
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	got := watchSummary(events)
+	want := map[string]WatchEventType{
+		"disk:/w/new.txt":                         WatchCreated,
+		"disk:/w/edit.txt":                        WatchModified,
+		"disk:/w/gone.txt":                        WatchDeleted,
+		"disk:/w/dir -> disk:/w/moved":            WatchMoved,
+		"disk:/w/file.txt -> disk:/w/renamed.txt": WatchMoved,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v", got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("events = %v, missing %s %s", got, v, k)
+		}
+	}
+}
+
+func TestWatcherPersistsSnapshot(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	ctx := context.Background()
+	state := filepath.Join(t.TempDir(), "watch.json")
+	srv.PutFile("disk:/w/a.txt", []byte("a"), time.Now())
+
+	w, err := client.NewWatcher(WatcherConfig{Root: "disk:/w", StatePath: state})
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+	if _, err := w.Poll(ctx); err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+
+	srv.PutFile("disk:/w/b.txt", []byte("b"), time.Now())
+	restarted, err := client.NewWatcher(WatcherConfig{Root: "disk:/w", StatePath: state})
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+	events, err := restarted.Poll(ctx)
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if got := watchSummary(events); len(got) != 1 || got["disk:/w/b.txt"] != WatchCreated {
+		t.Fatalf("events after restart = %v", got)
+	}
+}
+
+func TestWatcherRunEmitsEvents(t *testing.T) {
+	client, srv := newFakeDiskClient(t)
+	srv.Mkdir("disk:/w")
+	w, err := client.NewWatcher(WatcherConfig{Root: "disk:/w", Interval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	time.Sleep(30 * time.Millisecond)
+	srv.PutFile("disk:/w/a.txt", []byte("a"), time.Now())
+
+	select {
+	case e := <-w.Events():
+		if e.Type != WatchCreated || e.Path != "disk:/w/a.txt" {
+			t.Fatalf("event = %+v", e)
+		}
+	case <-ctx.Done():
+		t.Fatal("no event")
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("run = %v", err)
+	}
+	if _, ok := <-w.Events(); ok {
+		t.Fatal("events channel not closed")
+	}
+}
+
+func TestWatcherPollWithMetaCache(t *testing.T) {
+	client, srv, _ := newCachedClient(t, MetaCacheConfig{TTL: time.Hour})
+	ctx := context.Background()
+	srv.PutFile("disk:/w/a.txt", []byte("a"), time.Now())
+
+	w, err := client.NewWatcher(WatcherConfig{Root: "disk:/w"})
+	if err != nil {
+		t.Fatalf("new watcher: %v", err)
+	}
+	if _, err := client.Resources.GetMeta(ctx, ResourceGetRequest{Path: "disk:/w"}); err != nil {
+		t.Fatalf("get meta: %v", err)
+	}
+	if events, err := w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Fatalf("baseline poll = %v, %v", events, err)
+	}
+
+	srv.PutFile("disk:/w/b.txt", []byte("b"), time.Now())
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatalf("poll: %v", err)
+	}
+	if got := watchSummary(events); len(got) != 1 || got["disk:/w/b.txt"] != WatchCreated {
+		t.Fatalf("events = %v", got)
+	}
+}
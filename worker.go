@@ -12,13 +12,21 @@ type OperationEvent struct {
 	Status string
 	Done   bool
 	Err    error
+	// Elapsed is the time since the operation was first watched.
+	Elapsed time.Duration
 }
 
 type watchState struct {
 	ref      OperationRef
-	handlers []func(OperationEvent)
+	handlers []*watchHandler
 	interval time.Duration
 	nextPoll time.Time
+	watched  time.Time
+}
+
+// watchHandler boxes a handler so it can be found again to unwatch it.
+type watchHandler struct {
+	fn func(OperationEvent)
 }
 
 type OperationWorker struct {
@@ -87,8 +95,15 @@ func (w *OperationWorker) Stop(ctx context.Context) error {
 }
 
 func (w *OperationWorker) Watch(ref OperationRef, handler func(OperationEvent)) error {
+	_, err := w.watch(ref, handler)
+	return err
+}
+
+// watch is Watch returning a function that removes handler again. The
+// operation stops being polled once it has no handlers left.
+func (w *OperationWorker) watch(ref OperationRef, handler func(OperationEvent)) (func(), error) {
 	if handler == nil {
-		return errors.New("handler is required")
+		return nil, errors.New("handler is required")
 	}
 	if ref.ID == "" && ref.Href != "" {
 		if parsed := operationRefFromLink(&Link{Href: ref.Href}); parsed != nil {
@@ -97,7 +112,7 @@ func (w *OperationWorker) Watch(ref OperationRef, handler func(OperationEvent))
 		}
 	}
 	if ref.ID == "" {
-		return errors.New("operation id is required")
+		return nil, errors.New("operation id is required")
 	}
 
 	w.mu.Lock()
@@ -108,11 +123,38 @@ func (w *OperationWorker) Watch(ref OperationRef, handler func(OperationEvent))
 			ref:      ref,
 			interval: w.cfg.PollInterval,
 			nextPoll: time.Now(),
+			watched:  time.Now(),
 		}
 		w.watchers[ref.ID] = state
 	}
-	state.handlers = append(state.handlers, handler)
-	return nil
+	h := &watchHandler{fn: handler}
+	state.handlers = append(state.handlers, h)
+	return func() { w.unwatch(ref.ID, h) }, nil
+}
+
+func (w *OperationWorker) unwatch(id string, h *watchHandler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	state, ok := w.watchers[id]
+	if !ok {
+		return
+	}
+	for i, existing := range state.handlers {
+		if existing == h {
+			state.handlers = append(state.handlers[:i:i], state.handlers[i+1:]...)
+			break
+		}
+	}
+	if len(state.handlers) == 0 {
+		delete(w.watchers, id)
+	}
+}
+
+// Len reports how many operations are currently being watched.
+func (w *OperationWorker) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.watchers)
 }
 
 func (w *OperationWorker) loop(ctx context.Context) {
@@ -143,18 +185,19 @@ func (w *OperationWorker) tick(ctx context.Context) {
 	w.mu.Unlock()
 
 	for _, state := range states {
+		handlers := w.handlers(state)
 		status, err := w.client.Operations.GetStatus(ctx, OperationStatusRequest{OperationID: state.ref.ID})
-		event := OperationEvent{Ref: state.ref}
+		event := OperationEvent{Ref: state.ref, Elapsed: time.Since(state.watched)}
 		if err != nil {
 			event.Err = err
 			w.bump(state.ref.ID, true)
-			w.dispatch(state.handlers, event)
+			w.dispatch(handlers, event)
 			continue
 		}
 
 		event.Status = status.Status
 		event.Done = status.IsTerminal()
-		w.dispatch(state.handlers, event)
+		w.dispatch(handlers, event)
 
 		if event.Done {
 			w.remove(state.ref.ID)
@@ -182,18 +225,25 @@ func (w *OperationWorker) bump(id string, onError bool) {
 	state.nextPoll = time.Now().Add(w.client.jitter(next, w.cfg.Jitter))
 }
 
+func (w *OperationWorker) handlers(state *watchState) []*watchHandler {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]*watchHandler(nil), state.handlers...)
+}
+
 func (w *OperationWorker) remove(id string) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	delete(w.watchers, id)
 }
 
-func (w *OperationWorker) dispatch(handlers []func(OperationEvent), event OperationEvent) {
+func (w *OperationWorker) dispatch(handlers []*watchHandler, event OperationEvent) {
 	if w.client.hooks.OnOperationEvent != nil {
 		w.client.hooks.OnOperationEvent(event)
 	}
+	w.client.log.operation(event)
 	for _, h := range handlers {
-		hCopy := h
+		hCopy := h.fn
 		eventCopy := event
 		go hCopy(eventCopy)
 	}
@@ -44,6 +44,9 @@ func TestOperationWorkerLifecycle(t *testing.T) {
 	}); err != nil {
 		t.Fatalf("watch: %v", err)
 	}
+	if n := client.Worker.Len(); n != 1 {
+		t.Fatalf("len = %d want 1", n)
+	}
 
 	timeout := time.After(2 * time.Second)
 	for {
@@ -53,6 +56,9 @@ func TestOperationWorkerLifecycle(t *testing.T) {
 				if e.Status != "success" {
 					t.Fatalf("status=%s", e.Status)
 				}
+				if e.Elapsed <= 0 {
+					t.Fatalf("elapsed=%s", e.Elapsed)
+				}
 				if err := client.Worker.Stop(context.Background()); err != nil {
 					t.Fatalf("stop: %v", err)
 				}